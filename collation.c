@@ -0,0 +1,17 @@
+// Copyright 2010 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+#include <sqlite3.h>
+
+extern int goXCompare(void *pArg, int n1, const void *s1, int n2, const void *s2);
+
+int goSqlite3CreateCollation(sqlite3 *db, const char *zName, void *pArg) {
+	return sqlite3_create_collation_v2(db, zName, SQLITE_UTF8, pArg, goXCompare, 0);
+}
+
+extern void goXCollationNeeded(void *udp, sqlite3 *db, int eTextRep, const char *zName);
+
+int goSqlite3CollationNeeded(sqlite3 *db, void *udp) {
+	return sqlite3_collation_needed(db, udp, goXCollationNeeded);
+}
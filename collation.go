@@ -0,0 +1,92 @@
+// Copyright 2010 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sqlite
+
+/*
+#include <sqlite3.h>
+#include <stdlib.h>
+
+int goSqlite3CreateCollation(sqlite3 *db, const char *zName, void *pArg);
+int goSqlite3CollationNeeded(sqlite3 *db, void *udp);
+*/
+import "C"
+
+import (
+	"fmt"
+	"unsafe"
+)
+
+// Collation is the expected signature of a collating function implemented in Go.
+// It must return a negative, zero or positive integer if a is less than, equal to or greater than b.
+type Collation func(a, b string) int
+
+type sqliteCollation struct {
+	f Collation
+}
+
+//export goXCompare
+func goXCompare(pArg unsafe.Pointer, n1 C.int, s1 unsafe.Pointer, n2 C.int, s2 unsafe.Pointer) C.int {
+	arg := (*sqliteCollation)(pArg)
+	return C.int(arg.f(C.GoStringN((*C.char)(s1), n1), C.GoStringN((*C.char)(s2), n2)))
+}
+
+// CreateCollation adds or removes (when f is nil) a collating sequence:
+//
+//	SELECT name FROM users ORDER BY name COLLATE fr_FR
+//
+// Cannot be used with Go >= 1.6 and cgocheck enabled.
+// (See http://sqlite.org/c3ref/create_collation.html)
+func (c *Conn) CreateCollation(name string, f Collation) error {
+	zName := C.CString(name)
+	defer C.free(unsafe.Pointer(zName))
+	if f == nil {
+		if len(c.collations) > 0 {
+			delete(c.collations, name)
+		}
+		return c.error(C.sqlite3_create_collation_v2(c.db, zName, C.SQLITE_UTF8, nil, nil, nil),
+			fmt.Sprintf("<Conn.CreateCollation(%q)", name))
+	}
+	// To make sure it is not gced, keep a reference in the connection.
+	coll := &sqliteCollation{f}
+	rv := C.goSqlite3CreateCollation(c.db, zName, unsafe.Pointer(coll))
+	if rv != C.SQLITE_OK {
+		return c.error(rv, fmt.Sprintf("Conn.CreateCollation(%q)", name))
+	}
+	if len(c.collations) == 0 {
+		c.collations = make(map[string]*sqliteCollation)
+	}
+	c.collations[name] = coll
+	return nil
+}
+
+// CollationNeeded is the callback function signature.
+// It is invoked when an undefined collating sequence is required
+// and should register it with Conn.CreateCollation.
+type CollationNeeded func(udp interface{}, c *Conn, name string)
+
+type sqliteCollationNeeded struct {
+	f   CollationNeeded
+	udp interface{}
+	c   *Conn
+}
+
+//export goXCollationNeeded
+func goXCollationNeeded(udp unsafe.Pointer, db unsafe.Pointer, eTextRep C.int, zName *C.char) {
+	arg := (*sqliteCollationNeeded)(udp)
+	arg.f(arg.udp, arg.c, C.GoString(zName))
+}
+
+// CollationNeeded registers a callback to be invoked whenever an undefined collating sequence is required.
+// Cannot be used with Go >= 1.6 and cgocheck enabled.
+// (See http://sqlite.org/c3ref/collation_needed.html)
+func (c *Conn) CollationNeeded(f CollationNeeded, udp interface{}) error {
+	if f == nil {
+		c.collationNeeded = nil
+		return c.error(C.sqlite3_collation_needed(c.db, nil, nil), "<Conn.CollationNeeded")
+	}
+	// To make sure it is not gced, keep a reference in the connection.
+	c.collationNeeded = &sqliteCollationNeeded{f, udp, c}
+	return c.error(C.goSqlite3CollationNeeded(c.db, unsafe.Pointer(c.collationNeeded)), "Conn.CollationNeeded")
+}
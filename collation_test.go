@@ -0,0 +1,84 @@
+// Copyright 2010 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sqlite_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/bmizerany/assert"
+	. "github.com/gwenn/gosqlite"
+)
+
+func reverse(a, b string) int {
+	return -strings.Compare(a, b)
+}
+
+func selectNames(t *testing.T, db *Conn, sql string) []string {
+	s, err := db.Prepare(sql)
+	checkNoError(t, err, "couldn't prepare statement: %s")
+	defer checkFinalize(s, t)
+	var names []string
+	err = s.Select(func(s *Stmt) error {
+		var name string
+		if err := s.Scan(&name); err != nil {
+			return err
+		}
+		names = append(names, name)
+		return nil
+	})
+	checkNoError(t, err, "couldn't execute statement: %s")
+	return names
+}
+
+func TestCreateCollation(t *testing.T) {
+	skipIfCgoCheckActive(t)
+	db := open(t)
+	defer checkClose(db, t)
+
+	err := db.CreateCollation("reverse", reverse)
+	checkNoError(t, err, "couldn't create collation: %s")
+	err = db.FastExec(`CREATE TABLE test (name TEXT COLLATE reverse);
+		CREATE INDEX test_name ON test (name);
+		INSERT INTO test VALUES ('b'), ('a'), ('c');`)
+	checkNoError(t, err, "%s")
+
+	assert.Equal(t, []string{"c", "b", "a"}, selectNames(t, db, "SELECT name FROM test ORDER BY name"))
+	assert.Equal(t, []string{"a", "b", "c"}, selectNames(t, db, "SELECT name FROM test ORDER BY name COLLATE binary"))
+	err = db.FastExec("DROP TABLE test")
+	checkNoError(t, err, "%s")
+
+	err = db.CreateCollation("reverse", nil)
+	checkNoError(t, err, "couldn't remove collation: %s")
+	_, err = db.Prepare("SELECT 1 ORDER BY 1 COLLATE reverse")
+	assert.T(t, err != nil, "expected error with removed collation")
+}
+
+func TestCollationNeeded(t *testing.T) {
+	skipIfCgoCheckActive(t)
+	db := open(t)
+	defer checkClose(db, t)
+
+	var needed []string
+	err := db.CollationNeeded(func(udp interface{}, c *Conn, name string) {
+		needed = append(needed, name)
+		if name == "nocase_reverse" {
+			checkNoError(t, c.CreateCollation(name, func(a, b string) int {
+				return reverse(strings.ToLower(a), strings.ToLower(b))
+			}), "couldn't create collation: %s")
+		}
+	}, nil)
+	checkNoError(t, err, "couldn't register collation needed callback: %s")
+
+	assert.Equal(t, []string{"b", "A"}, selectNames(t, db,
+		"SELECT name FROM (SELECT 'A' AS name UNION ALL SELECT 'b') ORDER BY name COLLATE nocase_reverse"))
+	assert.Equal(t, []string{"nocase_reverse"}, needed)
+
+	_, err = db.Prepare("SELECT 1 ORDER BY 1 COLLATE unknown")
+	assert.T(t, err != nil, "expected error with unknown collation")
+
+	err = db.CollationNeeded(nil, nil)
+	checkNoError(t, err, "couldn't unregister collation needed callback: %s")
+}
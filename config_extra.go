@@ -25,6 +25,16 @@ import (
 	"unsafe"
 )
 
+func init() {
+	loadExtension = func(c *Conn, file string) error {
+		if err := c.EnableLoadExtension(true); err != nil {
+			return err
+		}
+		defer c.EnableLoadExtension(false)
+		return c.LoadExtension(file)
+	}
+}
+
 // EnableLoadExtension enables or disables extension loading.
 // (See http://sqlite.org/c3ref/enable_load_extension.html)
 func (c *Conn) EnableLoadExtension(b bool) error {
@@ -0,0 +1,225 @@
+// Copyright 2010 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sqlite
+
+import (
+	"context"
+	"database/sql/driver"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Connector implements database/sql/driver.Connector for a fixed DSN.
+// It is returned by the driver OpenConnector method (used by sql.Open) or by NewConnector (for sql.OpenDB).
+//
+// Besides SQLite URI parameters (http://sqlite.org/uri.html), the DSN may contain
+// the following parameters which are removed before opening the database:
+//
+//	_busy_timeout=5000|5s          Conn.BusyTimeout (milliseconds or Go duration)
+//	_journal_mode=WAL              Conn.SetJournalMode
+//	_synchronous=NORMAL|0..3       Conn.SetSynchronous (OFF, NORMAL, FULL, EXTRA)
+//	_foreign_keys=true             Conn.EnableFKey
+//	_cache_size=10                 Conn.SetCacheSize (prepared statements cache)
+//	_txlock=immediate              transaction type used by Begin (deferred, immediate, exclusive)
+//	_time_layout=2006-01-02        Conn.DefaultTimeLayout (empty for Unix time)
+//	_load_extension=path/to/ext    Conn.LoadExtension (may be repeated, requires the 'all' build tag)
+//
+// For example:
+//
+//	db, err := sql.Open("sqlite3", "file:test.db?cache=shared&_busy_timeout=5000&_foreign_keys=true&_txlock=immediate")
+type Connector struct {
+	d          *impl
+	name       string // DSN without driver specific parameters
+	txLock     TransactionType
+	config     []func(c *Conn) error
+	converters *Converters
+}
+
+// NewConnector creates a connector for the default driver.
+// It can be used with sql.OpenDB.
+func NewConnector(dsn string) (*Connector, error) {
+	return newConnector(&impl{open: defaultOpen}, dsn)
+}
+
+// OpenConnector parses the DSN driver specific parameters once for all connections.
+func (d *impl) OpenConnector(name string) (driver.Connector, error) {
+	return newConnector(d, name)
+}
+
+func newConnector(d *impl, dsn string) (*Connector, error) {
+	c := &Connector{d: d, name: dsn}
+	i := strings.IndexByte(dsn, '?')
+	if i < 0 {
+		return c, nil
+	}
+	var params []string
+	for _, param := range strings.Split(dsn[i+1:], "&") {
+		if !strings.HasPrefix(param, "_") {
+			if param != "" {
+				params = append(params, param)
+			}
+			continue
+		}
+		key, value := param, ""
+		if j := strings.IndexByte(param, '='); j >= 0 {
+			key, value = param[:j], param[j+1:]
+		}
+		value, err := url.QueryUnescape(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid DSN parameter %s: %s", key, err)
+		}
+		if err = c.parseParam(key, value); err != nil {
+			return nil, err
+		}
+	}
+	c.name = dsn[:i]
+	if len(params) > 0 {
+		c.name += "?" + strings.Join(params, "&")
+	}
+	return c, nil
+}
+
+func (c *Connector) parseParam(key, value string) error {
+	invalid := func() error {
+		return fmt.Errorf("invalid DSN parameter %s: %q", key, value)
+	}
+	switch key {
+	case "_busy_timeout":
+		d, err := parseDuration(value)
+		if err != nil {
+			return invalid()
+		}
+		c.config = append(c.config, func(db *Conn) error {
+			return db.BusyTimeout(d)
+		})
+	case "_journal_mode":
+		c.config = append(c.config, func(db *Conn) error {
+			_, err := db.SetJournalMode("", value)
+			return err
+		})
+	case "_synchronous":
+		mode, err := parseSynchronous(value)
+		if err != nil {
+			return invalid()
+		}
+		c.config = append(c.config, func(db *Conn) error {
+			return db.SetSynchronous("", mode)
+		})
+	case "_foreign_keys":
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return invalid()
+		}
+		c.config = append(c.config, func(db *Conn) error {
+			_, err := db.EnableFKey(b)
+			return err
+		})
+	case "_cache_size":
+		size, err := strconv.Atoi(value)
+		if err != nil {
+			return invalid()
+		}
+		c.config = append(c.config, func(db *Conn) error {
+			db.SetCacheSize(size)
+			return nil
+		})
+	case "_txlock":
+		switch strings.ToLower(value) {
+		case "deferred":
+			c.txLock = Deferred
+		case "immediate":
+			c.txLock = Immediate
+		case "exclusive":
+			c.txLock = Exclusive
+		default:
+			return invalid()
+		}
+	case "_time_layout":
+		c.config = append(c.config, func(db *Conn) error {
+			db.DefaultTimeLayout = value
+			return nil
+		})
+	case "_load_extension":
+		if loadExtension == nil {
+			return fmt.Errorf("DSN parameter %s requires the 'all' build tag", key)
+		}
+		c.config = append(c.config, func(db *Conn) error {
+			return loadExtension(db, value)
+		})
+	default:
+		return fmt.Errorf("unknown DSN parameter %s", key)
+	}
+	return nil
+}
+
+// parseDuration accepts milliseconds or a Go duration.
+func parseDuration(value string) (time.Duration, error) {
+	if ms, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return time.Duration(ms) * time.Millisecond, nil
+	}
+	return time.ParseDuration(value)
+}
+
+func parseSynchronous(value string) (int, error) {
+	switch strings.ToUpper(value) {
+	case "OFF":
+		return 0, nil
+	case "NORMAL":
+		return 1, nil
+	case "FULL":
+		return 2, nil
+	case "EXTRA":
+		return 3, nil
+	}
+	mode, err := strconv.Atoi(value)
+	if err != nil || mode < 0 || mode > 3 {
+		return -1, fmt.Errorf("invalid synchronous mode: %q", value)
+	}
+	return mode, nil
+}
+
+// loadExtension is set when extension loading is available (see config_extra.go).
+var loadExtension func(c *Conn, file string) error
+
+// Connect opens and configures a new connection.
+func (c *Connector) Connect(ctx context.Context) (driver.Conn, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	db, err := c.d.open(c.name)
+	if err != nil {
+		return nil, err
+	}
+	for _, config := range c.config {
+		if err = config(db); err != nil {
+			_ = db.Close()
+			return nil, err
+		}
+	}
+	if c.converters != nil {
+		db.SetConverters(c.converters)
+	}
+	if c.d.configure != nil {
+		if err = c.d.configure(db); err != nil {
+			_ = db.Close()
+			return nil, err
+		}
+	}
+	return &conn{c: db, txLock: c.txLock}, nil
+}
+
+// SetConverters specifies the registry used by the connections opened by this connector
+// (see Conn.SetConverters).
+func (c *Connector) SetConverters(r *Converters) {
+	c.converters = r
+}
+
+// Driver returns the underlying driver.
+func (c *Connector) Driver() driver.Driver {
+	return c.d
+}
@@ -0,0 +1,169 @@
+// Copyright 2010 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sqlite
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+)
+
+// ContextError is returned when an operation is interrupted
+// because its context is cancelled or its deadline is exceeded.
+type ContextError struct {
+	Err   error // ctx.Err()
+	Cause error // SQLite error (usually ErrInterrupt), may be nil
+}
+
+func (e ContextError) Error() string {
+	if e.Cause == nil {
+		return e.Err.Error()
+	}
+	return fmt.Sprintf("%s (%s)", e.Err, e.Cause)
+}
+
+// Unwrap returns ctx.Err() so that errors.Is(err, context.Canceled) works.
+func (e ContextError) Unwrap() error {
+	return e.Err
+}
+
+// contextError wraps err when ctx is done.
+func contextError(ctx context.Context, err error) error {
+	if err == nil || err == io.EOF {
+		return err
+	}
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return ContextError{Err: ctxErr, Cause: err}
+	}
+	return err
+}
+
+// watch starts a goroutine that interrupts the connection when ctx is done.
+// The returned function must be called, once the operation completes, to stop watching.
+// Interrupting the connection aborts all its pending operations.
+func (c *Conn) watch(ctx context.Context) (stop func()) {
+	done := ctx.Done()
+	if done == nil {
+		return func() {}
+	}
+	stopped := make(chan struct{})
+	finished := make(chan struct{})
+	go func() {
+		defer close(finished)
+		select {
+		case <-done:
+			c.Interrupt()
+		case <-stopped:
+		}
+	}()
+	return func() {
+		close(stopped)
+		<-finished // make sure the interrupt does not happen after the operation
+	}
+}
+
+// ExecContext is like Exec but the execution is interrupted when ctx is done.
+// The returned error then wraps ctx.Err() (see ContextError).
+func (c *Conn) ExecContext(ctx context.Context, cmd string, args ...interface{}) error {
+	if err := ctx.Err(); err != nil {
+		return ContextError{Err: err}
+	}
+	defer c.watch(ctx)()
+	return contextError(ctx, c.Exec(cmd, args...))
+}
+
+// SelectContext is like Select but the execution is interrupted when ctx is done.
+// The returned error then wraps ctx.Err() (see ContextError).
+func (c *Conn) SelectContext(ctx context.Context, query string, rowCallbackHandler func(s *Stmt) error, args ...interface{}) error {
+	if err := ctx.Err(); err != nil {
+		return ContextError{Err: err}
+	}
+	defer c.watch(ctx)()
+	return contextError(ctx, c.Select(query, rowCallbackHandler, args...))
+}
+
+// OneValueContext is like OneValue but the execution is interrupted when ctx is done.
+// The returned error then wraps ctx.Err() (see ContextError).
+func (c *Conn) OneValueContext(ctx context.Context, query string, value interface{}, args ...interface{}) error {
+	if err := ctx.Err(); err != nil {
+		return ContextError{Err: err}
+	}
+	defer c.watch(ctx)()
+	return contextError(ctx, c.OneValue(query, value, args...))
+}
+
+// ExecContext is like Exec but the execution is interrupted when ctx is done.
+// The returned error then wraps ctx.Err() (see ContextError).
+func (s *Stmt) ExecContext(ctx context.Context, args ...interface{}) error {
+	if err := ctx.Err(); err != nil {
+		return ContextError{Err: err}
+	}
+	defer s.c.watch(ctx)()
+	return contextError(ctx, s.Exec(args...))
+}
+
+// SelectContext is like Select but the execution is interrupted when ctx is done.
+// The returned error then wraps ctx.Err() (see ContextError).
+func (s *Stmt) SelectContext(ctx context.Context, rowCallbackHandler func(s *Stmt) error, args ...interface{}) error {
+	if err := ctx.Err(); err != nil {
+		return ContextError{Err: err}
+	}
+	defer s.c.watch(ctx)()
+	return contextError(ctx, s.Select(rowCallbackHandler, args...))
+}
+
+// NextContext is like Next but the step is interrupted when ctx is done.
+// The returned error then wraps ctx.Err() (see ContextError).
+// For long result sets, prefer SelectContext which watches ctx only once.
+func (s *Stmt) NextContext(ctx context.Context) (bool, error) {
+	if err := ctx.Err(); err != nil {
+		s.Reset()
+		return false, ContextError{Err: err}
+	}
+	defer s.c.watch(ctx)()
+	ok, err := s.Next()
+	return ok, contextError(ctx, err)
+}
+
+// RunContext is like Run but the backup is stopped (and closed) when ctx is done.
+// The check happens between steps and during the sleep.
+// The returned error then wraps ctx.Err() (see ContextError).
+func (b *Backup) RunContext(ctx context.Context, npage int32, sleepNs time.Duration, c chan<- BackupStatus) error {
+	var err error
+	for {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			b.Close()
+			return ContextError{Err: ctxErr}
+		}
+		err = b.Step(npage)
+		if err != nil {
+			break
+		}
+		if c != nil {
+			c <- b.Status()
+		}
+		if sleepNs > 0 {
+			timer := time.NewTimer(sleepNs)
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+			case <-timer.C:
+			}
+		}
+	}
+	if err != Done {
+		b.Close()
+	} else {
+		if c != nil {
+			c <- b.Status()
+		}
+		err = b.Close()
+	}
+	if err != nil && err != Done {
+		return contextError(ctx, err)
+	}
+	return nil
+}
@@ -0,0 +1,101 @@
+// Copyright 2010 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sqlite_test
+
+import (
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/bmizerany/assert"
+	. "github.com/gwenn/gosqlite"
+)
+
+const endlessQuery = `WITH RECURSIVE cnt(x) AS (SELECT 1 UNION ALL SELECT x+1 FROM cnt)
+	SELECT count(*) FROM cnt`
+
+func checkContextError(t *testing.T, err error, expected error) {
+	cerr, ok := err.(ContextError)
+	if !ok {
+		t.Fatalf("got %#v; want ContextError", err)
+	}
+	assert.Equal(t, expected, cerr.Err)
+	assert.Equal(t, expected, cerr.Unwrap())
+}
+
+func TestContext(t *testing.T) {
+	db := open(t)
+	defer checkClose(db, t)
+	ctx := context.Background()
+
+	checkNoError(t, db.ExecContext(ctx, "CREATE TABLE test (x INTEGER); INSERT INTO test VALUES (?)", 1), "%s")
+	var x int
+	checkNoError(t, db.OneValueContext(ctx, "SELECT x FROM test", &x), "%s")
+	assert.Equal(t, 1, x)
+	assert.Equal(t, io.EOF, db.OneValueContext(ctx, "SELECT x FROM test WHERE 0", &x))
+
+	s, err := db.Prepare("SELECT x FROM test")
+	checkNoError(t, err, "couldn't prepare stmt: %s")
+	defer checkFinalize(s, t)
+	ok, err := s.NextContext(ctx)
+	checkNoError(t, err, "%s")
+	assert.T(t, ok)
+	checkNoError(t, s.Reset(), "%s")
+	n := 0
+	checkNoError(t, s.SelectContext(ctx, func(s *Stmt) error {
+		n++
+		return nil
+	}), "%s")
+	assert.Equal(t, 1, n)
+
+	// the connection is still usable once the query has been interrupted
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	err = db.OneValueContext(ctx, endlessQuery, &x)
+	checkContextError(t, err, context.DeadlineExceeded)
+	checkNoError(t, db.OneValueContext(context.Background(), "SELECT x FROM test", &x), "%s")
+	assert.Equal(t, 1, x)
+}
+
+func TestContextCancel(t *testing.T) {
+	db := open(t)
+	defer checkClose(db, t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	checkContextError(t, db.ExecContext(ctx, "CREATE TABLE test (x INTEGER)"), context.Canceled)
+	checkContextError(t, db.SelectContext(ctx, "SELECT 1", func(s *Stmt) error { return nil }), context.Canceled)
+
+	ctx, cancel = context.WithCancel(context.Background())
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		cancel()
+	}()
+	err := db.SelectContext(ctx, endlessQuery, func(s *Stmt) error { return nil })
+	checkContextError(t, err, context.Canceled)
+	if cerr := err.(ContextError); cerr.Cause == nil {
+		t.Error("expected interrupt error as cause")
+	}
+}
+
+func TestBackupContext(t *testing.T) {
+	dst := open(t)
+	defer checkClose(dst, t)
+	src := open(t)
+	defer checkClose(src, t)
+	fill(nil, src, 1000)
+
+	bck, err := NewBackup(dst, "main", src, "main")
+	checkNoError(t, err, "couldn't init backup: %#v")
+	checkNoError(t, bck.RunContext(context.Background(), 10, 0, nil), "couldn't do backup: %#v")
+
+	bck, err = NewBackup(dst, "main", src, "main")
+	checkNoError(t, err, "couldn't init backup: %#v")
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	checkContextError(t, bck.RunContext(ctx, 10, time.Second, nil), context.Canceled)
+	checkNoError(t, bck.Close(), "couldn't close backup twice: %#v")
+}
@@ -0,0 +1,205 @@
+// Copyright 2010 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sqlite
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// BindConverter converts a Go value of a registered type to a value supported by Stmt.BindByIndex
+// (usually nil, int64, float64, bool, string, []byte or time.Time).
+type BindConverter func(value interface{}) (interface{}, error)
+
+// ScanConverter stores a column value into dest, a pointer to a registered type.
+// src is nil, int64, float64, string or []byte (see Stmt.ScanValue).
+type ScanConverter func(src interface{}, dest interface{}) error
+
+// Converters is a registry of Go type converters used
+// by Stmt.BindByIndex and Stmt.ScanByIndex for types they don't support natively
+// (uint64 above MaxInt64, *big.Int, UUIDs, decimals, enums...).
+// The database/sql driver also uses it to accept such types as arguments.
+// It is safe for concurrent use: lookups don't take any lock
+// and registrations replace a copy of the converters.
+type Converters struct {
+	mu sync.Mutex   // serializes registrations
+	m  atomic.Value // *converterMaps (never modified once stored)
+}
+
+type converterMaps struct {
+	bind map[reflect.Type]BindConverter
+	scan map[reflect.Type]ScanConverter
+}
+
+// DefaultConverters is the registry used by connections without specific converters
+// (see Conn.SetConverters and Connector.SetConverters).
+var DefaultConverters = NewConverters()
+
+// NewConverters creates a registry with only the uint64 converters registered:
+// values up to MaxInt64 are bound as integers and greater ones as decimal text.
+func NewConverters() *Converters {
+	r := &Converters{}
+	r.m.Store(&converterMaps{
+		bind: map[reflect.Type]BindConverter{reflect.TypeOf(uint64(0)): bindUint64},
+		scan: map[reflect.Type]ScanConverter{reflect.TypeOf(uint64(0)): scanUint64},
+	})
+	return r
+}
+
+func bindUint64(value interface{}) (interface{}, error) {
+	u := value.(uint64)
+	if u > math.MaxInt64 {
+		return strconv.FormatUint(u, 10), nil
+	}
+	return int64(u), nil
+}
+
+func scanUint64(src interface{}, dest interface{}) error {
+	var u uint64
+	switch src := src.(type) {
+	case nil:
+	case int64:
+		if src < 0 {
+			return fmt.Errorf("negative value: %d", src)
+		}
+		u = uint64(src)
+	case float64:
+		if src < 0 || src >= math.MaxUint64 || src != math.Trunc(src) {
+			return fmt.Errorf("value out of uint64 range: %g", src)
+		}
+		u = uint64(src)
+	case string:
+		var err error
+		if u, err = strconv.ParseUint(strings.TrimSpace(src), 10, 64); err != nil {
+			return err
+		}
+	case []byte:
+		var err error
+		if u, err = strconv.ParseUint(strings.TrimSpace(string(src)), 10, 64); err != nil {
+			return err
+		}
+	}
+	*dest.(*uint64) = u
+	return nil
+}
+
+func (r *Converters) maps() *converterMaps {
+	return r.m.Load().(*converterMaps)
+}
+
+// RegisterBind registers f to convert values with the same type as sample before binding them:
+//
+//	converters.RegisterBind((*big.Int)(nil), func(v interface{}) (interface{}, error) {
+//		return v.(*big.Int).String(), nil
+//	})
+//
+// A nil f removes the converter.
+func (r *Converters) RegisterBind(sample interface{}, f BindConverter) {
+	t := reflect.TypeOf(sample)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	old := r.maps()
+	bind := make(map[reflect.Type]BindConverter, len(old.bind)+1)
+	for k, v := range old.bind {
+		bind[k] = v
+	}
+	if f == nil {
+		delete(bind, t)
+	} else {
+		bind[t] = f
+	}
+	r.m.Store(&converterMaps{bind: bind, scan: old.scan})
+}
+
+// RegisterScan registers f to scan column values into pointers to the type of sample:
+//
+//	converters.RegisterScan((*big.Int)(nil), func(src, dest interface{}) error {
+//		// dest is a **big.Int
+//	})
+//
+// A nil f removes the converter.
+func (r *Converters) RegisterScan(sample interface{}, f ScanConverter) {
+	t := reflect.TypeOf(sample)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	old := r.maps()
+	scan := make(map[reflect.Type]ScanConverter, len(old.scan)+1)
+	for k, v := range old.scan {
+		scan[k] = v
+	}
+	if f == nil {
+		delete(scan, t)
+	} else {
+		scan[t] = f
+	}
+	r.m.Store(&converterMaps{bind: old.bind, scan: scan})
+}
+
+func (r *Converters) bindConverter(value interface{}) (BindConverter, bool) {
+	bind := r.maps().bind
+	if len(bind) == 0 {
+		return nil, false
+	}
+	f, ok := bind[reflect.TypeOf(value)]
+	return f, ok
+}
+
+// convert applies the converter registered for the type of value, if any (ok is false otherwise).
+// The converted value must be natively supported by Stmt.BindByIndex.
+func (r *Converters) convert(value interface{}) (v interface{}, ok bool, err error) {
+	f, ok := r.bindConverter(value)
+	if !ok {
+		return value, false, nil
+	}
+	if v, err = f(value); err != nil {
+		return nil, true, err
+	}
+	if !isNative(v) {
+		return nil, true, fmt.Errorf("unsupported type %T returned by the %T converter", v, value)
+	}
+	return v, true, nil
+}
+
+// isNative tells if value is bound by Stmt.BindByIndex without conversion nor reflection.
+func isNative(value interface{}) bool {
+	switch value.(type) {
+	case nil, string, int, int32, int64, byte, bool, float32, float64, []byte, time.Time, ZeroBlobLength, Pointer:
+		return true
+	}
+	return false
+}
+
+func (r *Converters) scanConverter(dest interface{}) (ScanConverter, bool) {
+	t := reflect.TypeOf(dest)
+	if t == nil || t.Kind() != reflect.Ptr {
+		return nil, false
+	}
+	scan := r.maps().scan
+	if len(scan) == 0 {
+		return nil, false
+	}
+	f, ok := scan[t.Elem()]
+	return f, ok
+}
+
+// SetConverters specifies the registry used by this connection.
+// If r is nil, DefaultConverters is used.
+func (c *Conn) SetConverters(r *Converters) {
+	c.converters = r
+}
+
+// Converters returns the registry used by this connection.
+func (c *Conn) Converters() *Converters {
+	if c.converters == nil {
+		return DefaultConverters
+	}
+	return c.converters
+}
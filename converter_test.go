@@ -0,0 +1,142 @@
+// Copyright 2010 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sqlite_test
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"math"
+	"math/big"
+	"testing"
+
+	"github.com/bmizerany/assert"
+	. "github.com/gwenn/gosqlite"
+)
+
+func bigIntConverters() *Converters {
+	r := NewConverters()
+	r.RegisterBind((*big.Int)(nil), func(v interface{}) (interface{}, error) {
+		return v.(*big.Int).String(), nil
+	})
+	r.RegisterScan((*big.Int)(nil), func(src, dest interface{}) error {
+		if src == nil {
+			*dest.(**big.Int) = nil
+			return nil
+		}
+		i, ok := new(big.Int).SetString(fmt.Sprint(src), 10)
+		if !ok {
+			return fmt.Errorf("invalid big.Int: %v", src)
+		}
+		*dest.(**big.Int) = i
+		return nil
+	})
+	return r
+}
+
+// celsius implements driver.Valuer but a converter can be registered to override it.
+type celsius float64
+
+func (c celsius) Value() (driver.Value, error) {
+	return float64(c), nil
+}
+
+func TestValuerConverter(t *testing.T) {
+	db := open(t)
+	defer checkClose(db, t)
+	var v interface{}
+	checkNoError(t, db.OneValue("SELECT ?", &v, celsius(20)), "%s")
+	assert.Equal(t, float64(20), v)
+
+	r := NewConverters()
+	r.RegisterBind(celsius(0), func(v interface{}) (interface{}, error) {
+		return fmt.Sprintf("%g°C", v), nil
+	})
+	db.SetConverters(r)
+	checkNoError(t, db.OneValue("SELECT ?", &v, celsius(20)), "%s")
+	assert.Equal(t, "20°C", v)
+
+	// converters must return a natively supported type
+	r.RegisterBind(celsius(0), func(v interface{}) (interface{}, error) {
+		return struct{}{}, nil
+	})
+	err := db.OneValue("SELECT ?", &v, celsius(20))
+	assert.T(t, err != nil, "expected unsupported type error")
+}
+
+func TestConverters(t *testing.T) {
+	db := open(t)
+	defer checkClose(db, t)
+	checkNoError(t, db.FastExec("CREATE TABLE test (n TEXT, u TEXT)"), "%s")
+
+	err := db.Exec("INSERT INTO test VALUES (?, ?)", new(big.Int), nil)
+	assert.T(t, err != nil, "expected unsupported type error")
+
+	// uint64 converters are registered by default
+	var u uint64
+	checkNoError(t, db.OneValue("SELECT ?", &u, uint64(math.MaxUint64)), "%s")
+	assert.Equal(t, uint64(math.MaxUint64), u)
+	var v interface{}
+	checkNoError(t, db.OneValue("SELECT ?", &v, uint64(1)), "%s")
+	assert.Equal(t, int64(1), v)
+	err = db.OneValue("SELECT -1", &u)
+	assert.T(t, err != nil, "expected negative value error")
+
+	db.SetConverters(bigIntConverters())
+	n, _ := new(big.Int).SetString("123456789012345678901234567890", 10)
+	checkNoError(t, db.Exec("INSERT INTO test VALUES (?, ?)", n, uint64(math.MaxUint64)), "%s")
+	checkNoError(t, db.Exec("INSERT INTO test VALUES (?, ?)", nil, uint64(1)), "%s")
+
+	s, err := db.Prepare("SELECT n, u FROM test ORDER BY rowid")
+	checkNoError(t, err, "couldn't prepare stmt: %s")
+	defer checkFinalize(s, t)
+	var i *big.Int
+	checkStep(t, s)
+	checkNoError(t, s.Scan(&i, &u), "%s")
+	assert.Equal(t, n.String(), i.String())
+	assert.Equal(t, uint64(math.MaxUint64), u)
+	checkStep(t, s)
+	isNull, err := s.ScanByIndex(0, &i)
+	checkNoError(t, err, "%s")
+	assert.T(t, isNull)
+	assert.T(t, i == nil)
+
+	db.SetConverters(nil)
+	assert.Equal(t, DefaultConverters, db.Converters())
+}
+
+func TestDriverConverters(t *testing.T) {
+	c, err := NewConnector(":memory:")
+	checkNoError(t, err, "Error while creating connector: %s")
+	r := bigIntConverters()
+	c.SetConverters(r)
+	db := sql.OpenDB(c)
+	defer checkSqlDbClose(db, t)
+	_, err = db.Exec("CREATE TABLE test (n TEXT, u TEXT)")
+	checkNoError(t, err, "Error creating table: %s")
+
+	n, _ := new(big.Int).SetString("-123456789012345678901234567890", 10)
+	_, err = db.Exec("INSERT INTO test VALUES (?, ?)", n, uint64(math.MaxUint64))
+	checkNoError(t, err, "Error while inserting: %s")
+	var ns, us string
+	err = db.QueryRow("SELECT n, u FROM test").Scan(&ns, &us)
+	checkNoError(t, err, "Error while selecting: %s")
+	assert.Equal(t, n.String(), ns)
+	assert.Equal(t, "18446744073709551615", us)
+
+	// native and default database/sql conversions are still supported
+	type myInt int
+	_, err = db.Exec("INSERT INTO test VALUES (?, ?)", myInt(1), ZeroBlobLength(2))
+	checkNoError(t, err, "Error while inserting: %s")
+	_, err = db.Exec("INSERT INTO test VALUES (?, ?)", struct{}{}, nil)
+	assert.T(t, err != nil, "expected unsupported type error")
+
+	// converters must return a natively supported type
+	r.RegisterBind(uint64(0), func(v interface{}) (interface{}, error) {
+		return []string{"invalid"}, nil
+	})
+	_, err = db.Exec("INSERT INTO test VALUES (?, ?)", uint64(1), nil)
+	assert.T(t, err != nil, "expected unsupported type error")
+}
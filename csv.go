@@ -205,7 +205,7 @@ func (v *csvTab) readRow(r *yacr.Reader) error {
 	return nil
 }
 
-func (v *csvTab) BestIndex() error {
+func (v *csvTab) BestIndex(info *IndexInfo) error {
 	return nil
 }
 func (v *csvTab) Disconnect() error {
@@ -232,7 +232,7 @@ type csvTabCursor struct {
 func (vc *csvTabCursor) Close() error {
 	return vc.f.Close()
 }
-func (vc *csvTabCursor) Filter() error {
+func (vc *csvTabCursor) Filter(idxNum int, idxStr string, args []interface{}) error {
 	v := vc.vTab
 	/* seek back to start of first zRow */
 	v.eof = false
@@ -12,8 +12,11 @@ import (
 	"fmt"
 	"io"
 	"log"
+	"math"
 	"os"
 	"reflect"
+	"strconv"
+	"strings"
 	"time"
 )
 
@@ -33,7 +36,9 @@ type impl struct {
 	configure func(*Conn) error
 }
 type conn struct {
-	c *Conn
+	c          *Conn
+	txLock     TransactionType // see Connector
+	savepoints int             // number of nested transactions emulated with savepoints
 }
 type stmt struct {
 	s            *Stmt
@@ -84,18 +89,13 @@ var defaultOpen = func(name string) (*Conn, error) {
 // Open opens a new database connection.
 // ":memory:" for memory db,
 // "" for temp file db
+// (See Connector for driver specific parameters)
 func (d *impl) Open(name string) (driver.Conn, error) {
-	c, err := d.open(name)
+	c, err := newConnector(d, name)
 	if err != nil {
 		return nil, err
 	}
-	if d.configure != nil {
-		if err = d.configure(c); err != nil {
-			_ = c.Close()
-			return nil, err
-		}
-	}
-	return &conn{c}, nil
+	return c.Connect(context.Background())
 }
 
 // Unwrap gives access to underlying driver connection.
@@ -190,22 +190,61 @@ func (c *conn) Close() error {
 	return c.c.Close()
 }
 
+// CheckNamedValue accepts the types natively supported by Stmt.BindByIndex
+// and the ones registered in the connection Converters.
+// Other types are converted by database/sql.
+func (c *conn) CheckNamedValue(nv *driver.NamedValue) error {
+	if isNative(nv.Value) {
+		return nil
+	}
+	v, ok, err := c.c.Converters().convert(nv.Value)
+	if err != nil {
+		return err
+	} else if !ok {
+		return driver.ErrSkip
+	}
+	nv.Value = v
+	return nil
+}
+
 func (c *conn) Begin() (driver.Tx, error) {
 	if c.c.IsClosed() {
 		return nil, driver.ErrBadConn
 	}
-	if err := c.c.Begin(); err != nil {
+	if err := c.c.BeginTransaction(c.txLock); err != nil {
 		return nil, err
 	}
 	return c, nil
 }
 
+type txTypeKey struct{}
+
+// WithTransactionType returns a copy of ctx that makes database/sql BeginTx
+// start a transaction of type t instead of the default one (see Connector _txlock):
+//
+//	tx, err := db.BeginTx(sqlite.WithTransactionType(ctx, sqlite.Immediate), nil)
+func WithTransactionType(ctx context.Context, t TransactionType) context.Context {
+	return context.WithValue(ctx, txTypeKey{}, t)
+}
+
+// BeginTx starts a transaction of the type specified by the context (see WithTransactionType)
+// or by the DSN.
+// When a transaction is already active, a savepoint is created instead
+// and options are not applied.
 func (c *conn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
 	if c.c.IsClosed() {
 		return nil, driver.ErrBadConn
 	}
 	if !c.c.GetAutocommit() {
-		return nil, errors.New("Nested transactions are not supported")
+		if opts.ReadOnly {
+			return nil, errors.New("read-only nested transactions are not supported")
+		}
+		name := fmt.Sprintf("gosqlite_tx_%d", c.savepoints+1)
+		if err := c.c.Savepoint(name); err != nil {
+			return nil, err
+		}
+		c.savepoints++
+		return &savepoint{c: c, name: name}, nil
 	}
 	if err := c.c.SetQueryOnly("", opts.ReadOnly); err != nil {
 		return nil, err
@@ -222,14 +261,57 @@ func (c *conn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, e
 	default:
 		return nil, fmt.Errorf("isolation level %d is not supported", opts.Isolation)
 	}
-	return c.Begin()
+	t := c.txLock
+	if v, ok := ctx.Value(txTypeKey{}).(TransactionType); ok {
+		t = v
+	}
+	if err := c.c.BeginTransaction(t); err != nil {
+		return nil, err
+	}
+	return c, nil
 }
 
 func (c *conn) Commit() error {
-	return c.c.Commit()
+	err := c.c.Commit()
+	c.resetSavepoints()
+	return err
 }
 func (c *conn) Rollback() error {
-	return c.c.Rollback()
+	err := c.c.Rollback()
+	c.resetSavepoints()
+	return err
+}
+
+// resetSavepoints forgets the nested transactions once the outermost one is over.
+// When COMMIT or ROLLBACK fails and the transaction is still active, the savepoints are kept.
+func (c *conn) resetSavepoints() {
+	if c.c.GetAutocommit() {
+		c.savepoints = 0
+	}
+}
+
+// savepoint is a nested transaction.
+type savepoint struct {
+	c    *conn
+	name string
+}
+
+func (s *savepoint) Commit() error {
+	if err := s.c.c.ReleaseSavepoint(s.name); err != nil {
+		return err
+	}
+	s.c.savepoints--
+	return nil
+}
+func (s *savepoint) Rollback() error {
+	if err := s.c.c.RollbackSavepoint(s.name); err != nil {
+		return err
+	}
+	if err := s.c.c.ReleaseSavepoint(s.name); err != nil {
+		return err
+	}
+	s.c.savepoints--
+	return nil
 }
 
 func (s *stmt) Close() error {
@@ -356,16 +438,38 @@ func (r *rowsImpl) NextResultSet() error {
 	return nil
 }
 
+// ColumnTypeScanType returns a type suitable for scanning the column,
+// based on the declared type affinity (or on the current value storage class for expressions).
 func (r *rowsImpl) ColumnTypeScanType(index int) reflect.Type {
-	switch r.s.s.ColumnType(index) {
+	s := r.s.s
+	switch s.ColumnTypeAffinity(index) {
+	case Integral:
+		return reflect.TypeOf(int64(0))
+	case Real:
+		return reflect.TypeOf(float64(0))
+	case Textual:
+		return reflect.TypeOf("")
+	case Numerical:
+		declType := strings.ToUpper(s.ColumnDeclaredType(index))
+		if strings.Contains(declType, "BOOL") {
+			return reflect.TypeOf(false)
+		} else if s.c.ScanNumericalAsTime && (strings.Contains(declType, "DATE") || strings.Contains(declType, "TIME")) {
+			return reflect.TypeOf(time.Time{})
+		}
+		return reflect.TypeOf(float64(0))
+	}
+	if s.ColumnDeclaredType(index) != "" { // BLOB
+		return reflect.TypeOf([]byte{})
+	}
+	switch s.ColumnType(index) {
 	case Integer:
 		return reflect.TypeOf(int64(0))
 	case Float:
 		return reflect.TypeOf(float64(0))
 	case Text:
 		return reflect.TypeOf("")
-	case Null:
-		return reflect.TypeOf(nil)
+	case Null: // no row yet or NULL value
+		return reflect.TypeOf((*interface{})(nil)).Elem()
 	case Blob:
 		fallthrough
 	default:
@@ -377,6 +481,78 @@ func (r *rowsImpl) ColumnTypeDatabaseTypeName(index int) string {
 	return r.s.s.ColumnDeclaredType(index)
 }
 
+// ColumnTypeNullable tells if the table column of a result column may be null.
+// ok is false for expressions or when column metadata are not available (requires the 'all' build tag).
+func (r *rowsImpl) ColumnTypeNullable(index int) (nullable, ok bool) {
+	if columnMetadata == nil {
+		return false, false
+	}
+	c, err := columnMetadata(r.s.s, index)
+	if err != nil || c == nil {
+		return false, false
+	}
+	return !c.NotNull, true
+}
+
+// columnMetadata is set when column metadata are available (see meta_extra.go).
+var columnMetadata func(s *Stmt, index int) (*Column, error)
+
+// ColumnTypeLength returns the length of variable length column types (text and blob)
+// as declared (VARCHAR(30)) or math.MaxInt64 when unbounded.
+func (r *rowsImpl) ColumnTypeLength(index int) (length int64, ok bool) {
+	s := r.s.s
+	declType := s.ColumnDeclaredType(index)
+	affinity := s.ColumnTypeAffinity(index)
+	if affinity != Textual && (affinity != None || declType == "") {
+		return 0, false
+	}
+	if sizes := typeSizes(declType); len(sizes) > 0 {
+		return sizes[0], true
+	}
+	return math.MaxInt64, true
+}
+
+// ColumnTypePrecisionScale returns the precision and scale of numeric column types
+// as declared (DECIMAL(10,2)).
+func (r *rowsImpl) ColumnTypePrecisionScale(index int) (precision, scale int64, ok bool) {
+	s := r.s.s
+	switch s.ColumnTypeAffinity(index) {
+	case Numerical, Real:
+	default:
+		return 0, 0, false
+	}
+	sizes := typeSizes(s.ColumnDeclaredType(index))
+	switch len(sizes) {
+	case 1:
+		return sizes[0], 0, true
+	case 2:
+		return sizes[0], sizes[1], true
+	}
+	return 0, 0, false
+}
+
+// typeSizes parses the numeric arguments of a declared type like VARCHAR(30) or DECIMAL(10, 2).
+func typeSizes(declType string) []int64 {
+	i := strings.IndexByte(declType, '(')
+	j := strings.LastIndexByte(declType, ')')
+	if i < 0 || j < i {
+		return nil
+	}
+	args := strings.Split(declType[i+1:j], ",")
+	if len(args) > 2 {
+		return nil
+	}
+	sizes := make([]int64, len(args))
+	for k, arg := range args {
+		size, err := strconv.ParseInt(strings.TrimSpace(arg), 10, 64)
+		if err != nil {
+			return nil
+		}
+		sizes[k] = size
+	}
+	return sizes
+}
+
 func (c *Conn) result() driver.Result {
 	// TODO How to know that the last Stmt has done an INSERT? An authorizer?
 	id := c.LastInsertRowid()
@@ -9,7 +9,11 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"io/ioutil"
+	"math"
 	"math/rand"
+	"os"
+	"reflect"
 	"strconv"
 	"testing"
 	"time"
@@ -256,6 +260,148 @@ func TestCustomRegister2(t *testing.T) {
 	assert.Tf(t, fk, "foreign_keys = %t; want %t", fk, true)
 }
 
+func TestConnectorDSN(t *testing.T) {
+	db, err := sql.Open("sqlite3", "file:dsn.db?mode=memory&_busy_timeout=2s&_journal_mode=off"+
+		"&_synchronous=normal&_foreign_keys=true&_cache_size=5&_time_layout=2006-01-02")
+	checkNoError(t, err, "Error while opening db with DSN parameters: %s")
+	defer checkSqlDbClose(db, t)
+	conn := sqlite.Unwrap(db)
+	assert.Tf(t, conn != nil, "got %#v; want *sqlite.Conn", conn)
+	fk, err := conn.IsFKeyEnabled()
+	checkNoError(t, err, "Error while reading foreign_keys status: %s")
+	assert.Tf(t, fk, "foreign_keys = %t; want %t", fk, true)
+	mode, err := conn.JournalMode("")
+	checkNoError(t, err, "Error while reading journal mode: %s")
+	assert.Equal(t, "off", mode)
+	sync, err := conn.Synchronous("")
+	checkNoError(t, err, "Error while reading synchronous flag: %s")
+	assert.Equal(t, 1, sync)
+	_, maxSize := conn.CacheSize()
+	assert.Equal(t, 5, maxSize)
+	assert.Equal(t, "2006-01-02", conn.DefaultTimeLayout)
+
+	for _, dsn := range []string{":memory:?_unknown=1", ":memory:?_busy_timeout=x", ":memory:?_txlock=none",
+		":memory:?_synchronous=4", ":memory:?_foreign_keys=maybe", ":memory:?_cache_size=big"} {
+		_, err = sql.Open("sqlite3", dsn)
+		assert.Tf(t, err != nil, "expected error with %q", dsn)
+	}
+}
+
+func TestConnectorTxLock(t *testing.T) {
+	f, err := ioutil.TempFile("", "gosqlite-test")
+	checkNoError(t, err, "couldn't create temp file: %s")
+	checkNoError(t, f.Close(), "couldn't close temp file: %s")
+	defer os.Remove(f.Name())
+
+	c, err := sqlite.NewConnector(f.Name() + "?_txlock=immediate")
+	checkNoError(t, err, "Error while creating connector: %s")
+	db1 := sql.OpenDB(c)
+	defer checkSqlDbClose(db1, t)
+	db2, err := sql.Open("sqlite3", f.Name()+"?_busy_timeout=0")
+	checkNoError(t, err, "Error while opening db: %s")
+	defer checkSqlDbClose(db2, t)
+	_, err = db1.Exec("CREATE TABLE test (x INTEGER)")
+	checkNoError(t, err, "Error creating table: %s")
+
+	tx, err := db1.Begin()
+	checkNoError(t, err, "Error while beginning tx: %s")
+	// immediate transaction acquires the write lock even without any write
+	_, err = db2.Exec("INSERT INTO test VALUES (1)")
+	assert.T(t, err != nil, "expected busy error")
+	checkNoError(t, tx.Rollback(), "Error while rolling back tx: %s")
+	_, err = db2.Exec("INSERT INTO test VALUES (1)")
+	checkNoError(t, err, "Error while inserting: %s")
+
+	// DSN default overridden by context
+	tx, err = db1.BeginTx(sqlite.WithTransactionType(context.Background(), sqlite.Deferred), nil)
+	checkNoError(t, err, "Error while beginning tx: %s")
+	_, err = db2.Exec("INSERT INTO test VALUES (2)")
+	checkNoError(t, err, "Error while inserting: %s")
+	checkNoError(t, tx.Commit(), "Error while committing tx: %s")
+}
+
+func TestNestedTx(t *testing.T) {
+	db := sqlCreate(ddl, t)
+	defer checkSqlDbClose(db, t)
+	ctx := context.Background()
+	conn, err := db.Conn(ctx)
+	checkNoError(t, err, "Error while getting connection: %s")
+	defer conn.Close()
+
+	tx, err := conn.BeginTx(sqlite.WithTransactionType(ctx, sqlite.Immediate), nil)
+	checkNoError(t, err, "Error while beginning tx: %s")
+	_, err = tx.Exec(insert, "Bart")
+	checkNoError(t, err, "Error while inserting: %s")
+
+	nested, err := conn.BeginTx(ctx, nil)
+	checkNoError(t, err, "Error while beginning nested tx: %s")
+	_, err = nested.Exec(insert, "Lisa")
+	checkNoError(t, err, "Error while inserting: %s")
+	checkNoError(t, nested.Rollback(), "Error while rolling back nested tx: %s")
+
+	nested, err = conn.BeginTx(ctx, nil)
+	checkNoError(t, err, "Error while beginning nested tx: %s")
+	_, err = nested.Exec(insert, "Maggie")
+	checkNoError(t, err, "Error while inserting: %s")
+	checkNoError(t, nested.Commit(), "Error while committing nested tx: %s")
+
+	_, err = conn.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
+	assert.T(t, err != nil, "expected error with read-only nested tx")
+	checkNoError(t, tx.Commit(), "Error while committing tx: %s")
+
+	var names []string
+	rows, err := conn.QueryContext(ctx, "SELECT name FROM test ORDER BY id")
+	checkNoError(t, err, "Error while querying: %s")
+	defer checkSqlRowsClose(rows, t)
+	for rows.Next() {
+		var name string
+		checkNoError(t, rows.Scan(&name), "Error while scanning: %s")
+		names = append(names, name)
+	}
+	assert.Equal(t, []string{"Bart", "Maggie"}, names)
+}
+
+func TestColumnTypes(t *testing.T) {
+	db := sqlCreate("DROP TABLE IF EXISTS types;"+
+		"CREATE TABLE types (id INTEGER PRIMARY KEY, name VARCHAR(30) NOT NULL, descr TEXT,"+
+		" price DECIMAL(10, 2), ratio REAL, data BLOB, flag BOOLEAN, created DATETIME);"+
+		"INSERT INTO types VALUES (1, 'a', NULL, 1, 2, NULL, 1, '2006-01-02 15:04:05.000Z')", t)
+	defer checkSqlDbClose(db, t)
+	rows, err := db.Query("SELECT id, name, descr, price, ratio, data, flag, created, 1.5 FROM types")
+	checkNoError(t, err, "Error while querying: %s")
+	defer checkSqlRowsClose(rows, t)
+	types, err := rows.ColumnTypes()
+	checkNoError(t, err, "Error while getting column types: %s")
+
+	scanTypes := []reflect.Type{reflect.TypeOf(int64(0)), reflect.TypeOf(""), reflect.TypeOf(""),
+		reflect.TypeOf(float64(0)), reflect.TypeOf(float64(0)), reflect.TypeOf([]byte{}), reflect.TypeOf(false),
+		reflect.TypeOf(time.Time{}), reflect.TypeOf((*interface{})(nil)).Elem()} // no row yet for the expression
+	for i, ct := range types {
+		assert.Equal(t, scanTypes[i], ct.ScanType(), ct.Name())
+	}
+	assert.Equal(t, "VARCHAR(30)", types[1].DatabaseTypeName())
+
+	length, ok := types[1].Length()
+	assert.T(t, ok)
+	assert.Equal(t, int64(30), length)
+	length, ok = types[2].Length()
+	assert.T(t, ok)
+	assert.Equal(t, int64(math.MaxInt64), length)
+	_, ok = types[5].Length()
+	assert.T(t, ok, "blob is a variable length type")
+	_, ok = types[0].Length()
+	assert.T(t, !ok, "integer is not a variable length type")
+
+	precision, scale, ok := types[3].DecimalSize()
+	assert.T(t, ok)
+	assert.Equal(t, int64(10), precision)
+	assert.Equal(t, int64(2), scale)
+	_, _, ok = types[4].DecimalSize()
+	assert.T(t, !ok, "no precision declared")
+	_, _, ok = types[1].DecimalSize()
+	assert.T(t, !ok, "text has no precision")
+}
+
 // sql: Scan error on column index 0: unsupported driver -> Scan pair: []uint8 -> *time.Time
 func TestScanTimeFromView(t *testing.T) {
 	db := sqlCreate("CREATE VIEW v AS SELECT strftime('%Y-%m-%d %H:%M:%f', 'now') AS tic", t)
@@ -16,6 +16,11 @@ static inline void cXFunc(sqlite3_context *ctx, int argc, sqlite3_value **argv)
 	goXFunc(ctx, udf, goctx, argc, argv);
 }
 
+// Used by virtual tables to overload functions (see cXFindFunction)
+void goSqlite3ScalarFunction(sqlite3_context *ctx, int argc, sqlite3_value **argv) {
+	cXFunc(ctx, argc, argv);
+}
+
 static inline void cXStep(sqlite3_context *ctx, int argc, sqlite3_value **argv) {
 	void *udf = sqlite3_user_data(ctx);
 	goXStep(ctx, udf, argc, argv);
@@ -26,9 +31,22 @@ static inline void cXFinal(sqlite3_context *ctx) {
 	goXFinal(ctx, udf);
 }
 
+static inline void cXValue(sqlite3_context *ctx) {
+	void *udf = sqlite3_user_data(ctx);
+	goXValue(ctx, udf);
+}
+
+static inline void cXInverse(sqlite3_context *ctx, int argc, sqlite3_value **argv) {
+	void *udf = sqlite3_user_data(ctx);
+	goXInverse(ctx, udf, argc, argv);
+}
+
 int goSqlite3CreateScalarFunction(sqlite3 *db, const char *zFunctionName, int nArg, int eTextRep, void *pApp) {
 	return sqlite3_create_function_v2(db, zFunctionName, nArg, eTextRep, pApp, cXFunc, 0, 0, goXDestroy);
 }
 int goSqlite3CreateAggregateFunction(sqlite3 *db, const char *zFunctionName, int nArg, int eTextRep, void *pApp) {
 	return sqlite3_create_function_v2(db, zFunctionName, nArg, eTextRep, pApp, 0, cXStep, cXFinal, goXDestroy);
 }
+int goSqlite3CreateWindowFunction(sqlite3 *db, const char *zFunctionName, int nArg, int eTextRep, void *pApp) {
+	return sqlite3_create_window_function(db, zFunctionName, nArg, eTextRep, pApp, cXStep, cXFinal, cXValue, cXInverse, goXDestroy);
+}
@@ -45,10 +45,14 @@ static inline int my_value_type(sqlite3_value **argv, int i) {
 static inline int my_value_numeric_type(sqlite3_value **argv, int i) {
 	return sqlite3_value_numeric_type(argv[i]);
 }
+static inline unsigned int my_value_subtype(sqlite3_value **argv, int i) {
+	return sqlite3_value_subtype(argv[i]);
+}
 
 void goSqlite3SetAuxdata(sqlite3_context *ctx, int N, void *ad);
 int goSqlite3CreateScalarFunction(sqlite3 *db, const char *zFunctionName, int nArg, int eTextRep, void *pApp);
 int goSqlite3CreateAggregateFunction(sqlite3 *db, const char *zFunctionName, int nArg, int eTextRep, void *pApp);
+int goSqlite3CreateWindowFunction(sqlite3 *db, const char *zFunctionName, int nArg, int eTextRep, void *pApp);
 */
 import "C"
 
@@ -113,6 +117,8 @@ func (c *FunctionContext) Result(r interface{}) {
 		c.ResultBlob(r)
 	case ZeroBlobLength:
 		c.ResultZeroblob(r)
+	case Pointer:
+		c.ResultPointer(r.Type, r.Value)
 	case error:
 		c.ResultError(r.Error())
 	case Errno:
@@ -247,6 +253,19 @@ func (c *FunctionContext) ResultValue(i int) {
 	C.my_result_value((*C.sqlite3_context)(c.sc), c.argv, C.int(i))
 }
 
+// ResultSubType sets the subtype of the result of an SQL function.
+// Only the lower 8 bits of the subtype are preserved.
+// The function must be registered with FuncResultSubType.
+// (See sqlite3_result_subtype, http://sqlite.org/c3ref/result_subtype.html)
+func (c *Context) ResultSubType(t uint) {
+	C.sqlite3_result_subtype((*C.sqlite3_context)(c), C.uint(t))
+}
+
+// ResultSubType sets the subtype of the result of an SQL function.
+func (c *FunctionContext) ResultSubType(t uint) {
+	c.sc.ResultSubType(t)
+}
+
 // ResultZeroblob sets the result of an SQL function.
 // (See sqlite3_result_zeroblob, http://sqlite.org/c3ref/result_blob.html)
 func (c *Context) ResultZeroblob(n ZeroBlobLength) {
@@ -350,12 +369,23 @@ func (c *FunctionContext) NumericType(i int) Type {
 	return Type(C.my_value_numeric_type(c.argv, C.int(i)))
 }
 
+// SubType obtains a SQL function parameter value subtype (0 by default).
+// The function must be registered with FuncSubType.
+// The leftmost value is number 0.
+// (See sqlite3_value_subtype, http://sqlite.org/c3ref/value_subtype.html)
+func (c *FunctionContext) SubType(i int) uint {
+	return uint(C.my_value_subtype(c.argv, C.int(i)))
+}
+
 // Value obtains a SQL function parameter value depending on its type.
+// A Pointer is returned for a value passed with BindPointer or ResultPointer.
 func (c *FunctionContext) Value(i int) interface{} {
 	var value interface{}
 	switch c.Type(i) {
 	case Null:
-		value = nil
+		if p, ok := c.Arg(i).pointer(); ok {
+			value = p
+		}
 	case Text:
 		value = c.Text(i)
 	case Integer:
@@ -376,20 +406,24 @@ type ScalarFunction func(ctx *ScalarContext, nArg int)
 // StepFunction is the expected signature of step function implemented in Go
 type StepFunction func(ctx *AggregateContext, nArg int)
 
-// FinalFunction is the expected signature of final function implemented in Go
+// FinalFunction is the expected signature of final function implemented in Go.
+// It is not called when there is no row to aggregate.
 type FinalFunction func(ctx *AggregateContext)
 
 // DestroyDataFunction is the expected signature of function used to finalize user data.
 type DestroyDataFunction func(pApp interface{})
 
 type sqliteFunction struct {
-	scalar     ScalarFunction
-	step       StepFunction
-	final      FinalFunction
-	d          DestroyDataFunction
-	pApp       interface{}
-	scalarCtxs map[*ScalarContext]struct{}
-	aggrCtxs   map[*AggregateContext]struct{}
+	scalar       ScalarFunction
+	step         StepFunction
+	final        FinalFunction
+	value        FinalFunction
+	inverse      StepFunction
+	d            DestroyDataFunction
+	pApp         interface{}
+	scalarCtxs   map[*ScalarContext]struct{}
+	aggrCtxs     map[*AggregateContext]struct{}
+	finalIfEmpty bool // final is called even if there is no row to aggregate
 }
 
 //export goXAuxDataDestroy
@@ -419,43 +453,80 @@ func goXFunc(scp, udfp, ctxp unsafe.Pointer, argc int, argv unsafe.Pointer) {
 	c.argv = nil
 }
 
-//export goXStep
-func goXStep(scp, udfp unsafe.Pointer, argc int, argv unsafe.Pointer) {
-	udf := (*sqliteFunction)(udfp)
+// aggregateContext returns the AggregateContext associated to scp, creating it if needed.
+func aggregateContext(scp unsafe.Pointer, udf *sqliteFunction) *AggregateContext {
 	var cp unsafe.Pointer
 	cp = C.sqlite3_aggregate_context((*C.sqlite3_context)(scp), C.int(unsafe.Sizeof(cp)))
-	if cp != nil {
-		var c *AggregateContext
-		p := *(*unsafe.Pointer)(cp)
-		if p == nil {
-			c = new(AggregateContext)
-			c.sc = (*Context)(scp)
-			*(*unsafe.Pointer)(cp) = unsafe.Pointer(c)
-			// To make sure it is not cged
-			udf.aggrCtxs[c] = struct{}{}
-		} else {
-			c = (*AggregateContext)(p)
-		}
+	if cp == nil {
+		return nil
+	}
+	var c *AggregateContext
+	p := *(*unsafe.Pointer)(cp)
+	if p == nil {
+		c = new(AggregateContext)
+		c.sc = (*Context)(scp)
+		*(*unsafe.Pointer)(cp) = unsafe.Pointer(c)
+		// To make sure it is not cged
+		udf.aggrCtxs[c] = struct{}{}
+	} else {
+		c = (*AggregateContext)(p)
+	}
+	return c
+}
 
+//export goXStep
+func goXStep(scp, udfp unsafe.Pointer, argc int, argv unsafe.Pointer) {
+	udf := (*sqliteFunction)(udfp)
+	if c := aggregateContext(scp, udf); c != nil {
 		c.argv = (**C.sqlite3_value)(argv)
 		udf.step(c, argc)
 		c.argv = nil
 	}
 }
 
-//export goXFinal
-func goXFinal(scp, udfp unsafe.Pointer) {
+//export goXInverse
+func goXInverse(scp, udfp unsafe.Pointer, argc int, argv unsafe.Pointer) {
+	udf := (*sqliteFunction)(udfp)
+	if c := aggregateContext(scp, udf); c != nil {
+		c.argv = (**C.sqlite3_value)(argv)
+		udf.inverse(c, argc)
+		c.argv = nil
+	}
+}
+
+//export goXValue
+func goXValue(scp, udfp unsafe.Pointer) {
 	udf := (*sqliteFunction)(udfp)
 	cp := C.sqlite3_aggregate_context((*C.sqlite3_context)(scp), 0)
 	if cp != nil {
 		p := *(*unsafe.Pointer)(cp)
 		if p != nil {
 			c := (*AggregateContext)(p)
-			delete(udf.aggrCtxs, c)
 			c.sc = (*Context)(scp)
-			udf.final(c)
+			udf.value(c)
 		}
 	}
+}
+
+//export goXFinal
+func goXFinal(scp, udfp unsafe.Pointer) {
+	udf := (*sqliteFunction)(udfp)
+	var c *AggregateContext
+	cp := C.sqlite3_aggregate_context((*C.sqlite3_context)(scp), 0)
+	if cp != nil {
+		if p := *(*unsafe.Pointer)(cp); p != nil {
+			c = (*AggregateContext)(p)
+			delete(udf.aggrCtxs, c)
+		}
+	}
+	if c == nil { // no row
+		if !udf.finalIfEmpty {
+			return
+		}
+		c = new(AggregateContext)
+	}
+	c.sc = (*Context)(scp)
+	udf.final(c)
 	//	fmt.Printf("Contexts: %v\n", udf.aggrCtxts)
 }
 
@@ -467,7 +538,18 @@ func goXDestroy(pApp unsafe.Pointer) {
 	}
 }
 
-const sqliteDeterministic = 0x800 // C.SQLITE_DETERMINISTIC
+// FunctionFlag enumerates function flags
+// (See http://sqlite.org/c3ref/c_deterministic.html)
+type FunctionFlag int32
+
+// Function flags
+const (
+	FuncDeterministic FunctionFlag = 0x000000800 // C.SQLITE_DETERMINISTIC
+	FuncDirectOnly    FunctionFlag = 0x000080000 // C.SQLITE_DIRECTONLY
+	FuncSubType       FunctionFlag = 0x000100000 // C.SQLITE_SUBTYPE
+	FuncInnocuous     FunctionFlag = 0x000200000 // C.SQLITE_INNOCUOUS
+	FuncResultSubType FunctionFlag = 0x001000000 // C.SQLITE_RESULT_SUBTYPE
+)
 
 // CreateScalarFunction creates or redefines SQL scalar functions.
 // Cannot be used with Go >= 1.6 and cgocheck enabled.
@@ -475,10 +557,20 @@ const sqliteDeterministic = 0x800 // C.SQLITE_DETERMINISTIC
 // (See http://sqlite.org/c3ref/create_function.html)
 func (c *Conn) CreateScalarFunction(functionName string, nArg int32, deterministic bool, pApp interface{},
 	f ScalarFunction, d DestroyDataFunction) error {
-	var eTextRep C.int = C.SQLITE_UTF8
+	var flags FunctionFlag
 	if deterministic {
-		eTextRep = eTextRep | sqliteDeterministic
+		flags = FuncDeterministic
 	}
+	return c.CreateScalarFunctionFlags(functionName, nArg, flags, pApp, f, d)
+}
+
+// CreateScalarFunctionFlags creates or redefines SQL scalar functions with the specified flags
+// (combination of FuncDeterministic, FuncDirectOnly, FuncInnocuous, FuncSubType and FuncResultSubType).
+// Cannot be used with Go >= 1.6 and cgocheck enabled.
+// (See http://sqlite.org/c3ref/create_function.html)
+func (c *Conn) CreateScalarFunctionFlags(functionName string, nArg int32, flags FunctionFlag, pApp interface{},
+	f ScalarFunction, d DestroyDataFunction) error {
+	eTextRep := C.SQLITE_UTF8 | C.int(flags)
 	fname := C.CString(functionName)
 	defer C.free(unsafe.Pointer(fname))
 	if f == nil {
@@ -489,7 +581,7 @@ func (c *Conn) CreateScalarFunction(functionName string, nArg int32, determinist
 			fmt.Sprintf("<Conn.CreateScalarFunction(%q)", functionName))
 	}
 	// To make sure it is not gced, keep a reference in the connection.
-	udf := &sqliteFunction{f, nil, nil, d, pApp, make(map[*ScalarContext]struct{}), nil}
+	udf := &sqliteFunction{scalar: f, d: d, pApp: pApp, scalarCtxs: make(map[*ScalarContext]struct{})}
 	if len(c.udfs) == 0 {
 		c.udfs = make(map[string]*sqliteFunction)
 	}
@@ -504,21 +596,79 @@ func (c *Conn) CreateScalarFunction(functionName string, nArg int32, determinist
 // (See http://sqlite.org/c3ref/create_function.html)
 func (c *Conn) CreateAggregateFunction(functionName string, nArg int32, pApp interface{},
 	step StepFunction, final FinalFunction, d DestroyDataFunction) error {
+	return c.CreateAggregateFunctionFlags(functionName, nArg, 0, pApp, step, final, d)
+}
+
+// CreateAggregateFunctionFlags creates or redefines SQL aggregate functions with the specified flags.
+// (See CreateScalarFunctionFlags)
+// Cannot be used with Go >= 1.6 and cgocheck enabled.
+// (See http://sqlite.org/c3ref/create_function.html)
+func (c *Conn) CreateAggregateFunctionFlags(functionName string, nArg int32, flags FunctionFlag, pApp interface{},
+	step StepFunction, final FinalFunction, d DestroyDataFunction) error {
+	return c.createAggregateFunction(functionName, nArg, flags, pApp, step, final, d, false)
+}
+
+func (c *Conn) createAggregateFunction(functionName string, nArg int32, flags FunctionFlag, pApp interface{},
+	step StepFunction, final FinalFunction, d DestroyDataFunction, finalIfEmpty bool) error {
+	eTextRep := C.SQLITE_UTF8 | C.int(flags)
 	fname := C.CString(functionName)
 	defer C.free(unsafe.Pointer(fname))
 	if step == nil {
 		if len(c.udfs) > 0 {
 			delete(c.udfs, functionName)
 		}
-		return c.error(C.sqlite3_create_function_v2(c.db, fname, C.int(nArg), C.SQLITE_UTF8, nil, nil, nil, nil, nil),
+		return c.error(C.sqlite3_create_function_v2(c.db, fname, C.int(nArg), eTextRep, nil, nil, nil, nil, nil),
 			fmt.Sprintf("<Conn.CreateAggregateFunction(%q)", functionName))
 	}
 	// To make sure it is not gced, keep a reference in the connection.
-	udf := &sqliteFunction{nil, step, final, d, pApp, nil, make(map[*AggregateContext]struct{})}
+	udf := &sqliteFunction{step: step, final: final, d: d, pApp: pApp, aggrCtxs: make(map[*AggregateContext]struct{}),
+		finalIfEmpty: finalIfEmpty}
 	if len(c.udfs) == 0 {
 		c.udfs = make(map[string]*sqliteFunction)
 	}
 	c.udfs[functionName] = udf // FIXME same function name with different args is not supported
-	return c.error(C.goSqlite3CreateAggregateFunction(c.db, fname, C.int(nArg), C.SQLITE_UTF8, unsafe.Pointer(udf)),
+	return c.error(C.goSqlite3CreateAggregateFunction(c.db, fname, C.int(nArg), eTextRep, unsafe.Pointer(udf)),
 		fmt.Sprintf("Conn.CreateAggregateFunction(%q)", functionName))
 }
+
+// CreateWindowFunction creates or redefines SQL aggregate window functions.
+// value returns the current value of the aggregate and inverse removes
+// the oldest row from the current window (see http://sqlite.org/windowfunctions.html#udfwinfunc).
+// The window function can also be used as an ordinary aggregate function.
+// final, value and inverse are mandatory unless step is nil (to unregister the function).
+// Cannot be used with Go >= 1.6 and cgocheck enabled.
+// (See http://sqlite.org/c3ref/create_function.html)
+func (c *Conn) CreateWindowFunction(functionName string, nArg int32, pApp interface{},
+	step StepFunction, final FinalFunction, value FinalFunction, inverse StepFunction, d DestroyDataFunction) error {
+	return c.CreateWindowFunctionFlags(functionName, nArg, 0, pApp, step, final, value, inverse, d)
+}
+
+// CreateWindowFunctionFlags creates or redefines SQL aggregate window functions with the specified flags.
+// (See CreateScalarFunctionFlags and CreateWindowFunction)
+// Cannot be used with Go >= 1.6 and cgocheck enabled.
+// (See http://sqlite.org/c3ref/create_function.html)
+func (c *Conn) CreateWindowFunctionFlags(functionName string, nArg int32, flags FunctionFlag, pApp interface{},
+	step StepFunction, final FinalFunction, value FinalFunction, inverse StepFunction, d DestroyDataFunction) error {
+	eTextRep := C.SQLITE_UTF8 | C.int(flags)
+	fname := C.CString(functionName)
+	defer C.free(unsafe.Pointer(fname))
+	if step == nil {
+		if len(c.udfs) > 0 {
+			delete(c.udfs, functionName)
+		}
+		return c.error(C.sqlite3_create_function_v2(c.db, fname, C.int(nArg), eTextRep, nil, nil, nil, nil, nil),
+			fmt.Sprintf("<Conn.CreateWindowFunction(%q)", functionName))
+	}
+	if final == nil || value == nil || inverse == nil {
+		return c.specificError("Conn.CreateWindowFunction(%q): missing final, value or inverse function", functionName)
+	}
+	// To make sure it is not gced, keep a reference in the connection.
+	udf := &sqliteFunction{step: step, final: final, value: value, inverse: inverse, d: d, pApp: pApp,
+		aggrCtxs: make(map[*AggregateContext]struct{})}
+	if len(c.udfs) == 0 {
+		c.udfs = make(map[string]*sqliteFunction)
+	}
+	c.udfs[functionName] = udf // FIXME same function name with different args is not supported
+	return c.error(C.goSqlite3CreateWindowFunction(c.db, fname, C.int(nArg), eTextRep, unsafe.Pointer(udf)),
+		fmt.Sprintf("Conn.CreateWindowFunction(%q)", functionName))
+}
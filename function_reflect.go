@@ -0,0 +1,348 @@
+// Copyright 2010 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sqlite
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+)
+
+type argConverter func(c *FunctionContext, i int) (reflect.Value, error)
+type resultConverter func(c *FunctionContext, out []reflect.Value)
+type valueConverter func(c *FunctionContext, v reflect.Value)
+
+var errorType = reflect.TypeOf((*error)(nil)).Elem()
+
+// RegisterFunc creates or redefines an SQL scalar function implemented by fn,
+// a plain Go function like:
+//
+//	func(a int64, b string) (float64, error)
+//
+// Arguments and result are converted automatically based on their types:
+// bool, integers, floats, string, []byte and interface{} (see FunctionContext.Value) are supported,
+// as well as pointers to bool, integers, floats and string.
+// An SQL NULL argument is converted to nil for pointers, []byte and interface{}
+// and to the zero value otherwise. A nil pointer result is converted to NULL.
+// An integer argument or result that does not fit in the Go or SQL type is reported as an error.
+// fn may be variadic. It must return one value, optionally followed by an error.
+// A non-nil error is reported with ResultError.
+// Cannot be used with Go >= 1.6 and cgocheck enabled.
+func (c *Conn) RegisterFunc(name string, fn interface{}, deterministic bool) error {
+	v := reflect.ValueOf(fn)
+	if v.Kind() != reflect.Func {
+		return fmt.Errorf("RegisterFunc(%q): expected a function but got %T", name, fn)
+	}
+	t := v.Type()
+	args, err := argConverters(t)
+	if err != nil {
+		return fmt.Errorf("RegisterFunc(%q): %s", name, err)
+	}
+	result, err := resultConverterOf(t)
+	if err != nil {
+		return fmt.Errorf("RegisterFunc(%q): %s", name, err)
+	}
+	nArg := int32(t.NumIn())
+	if t.IsVariadic() {
+		nArg = -1
+	}
+	return c.CreateScalarFunction(name, nArg, deterministic, nil, func(ctx *ScalarContext, nArg int) {
+		in, err := callArgs(t, args, &ctx.FunctionContext, nArg)
+		if err != nil {
+			ctx.ResultError(err.Error())
+			return
+		}
+		result(&ctx.FunctionContext, v.Call(in))
+	}, nil)
+}
+
+// argConverters returns one converter per parameter of the function type t
+// (the element type is used for the variadic parameter).
+func argConverters(t reflect.Type) ([]argConverter, error) {
+	args := make([]argConverter, t.NumIn())
+	for i := range args {
+		at := t.In(i)
+		if t.IsVariadic() && i == len(args)-1 {
+			at = at.Elem()
+		}
+		conv, err := argConverterOf(at)
+		if err != nil {
+			return nil, err
+		}
+		args[i] = conv
+	}
+	return args, nil
+}
+
+// callArgs converts the nArg SQL arguments to Go values.
+func callArgs(t reflect.Type, args []argConverter, c *FunctionContext, nArg int) ([]reflect.Value, error) {
+	n := len(args)
+	if t.IsVariadic() {
+		n--
+		if nArg < n {
+			return nil, fmt.Errorf("wrong number of arguments: expected at least %d but got %d", n, nArg)
+		}
+	} else if nArg != n {
+		return nil, fmt.Errorf("wrong number of arguments: expected %d but got %d", n, nArg)
+	}
+	in := make([]reflect.Value, nArg)
+	for i := range in {
+		j := i
+		if j > n {
+			j = n // variadic
+		}
+		v, err := args[j](c, i)
+		if err != nil {
+			return nil, fmt.Errorf("argument %d: %s", i+1, err)
+		}
+		in[i] = v
+	}
+	return in, nil
+}
+
+func argConverterOf(t reflect.Type) (argConverter, error) {
+	switch t.Kind() {
+	case reflect.Bool:
+		return func(c *FunctionContext, i int) (reflect.Value, error) {
+			return reflect.ValueOf(c.Bool(i)).Convert(t), nil
+		}, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return func(c *FunctionContext, i int) (reflect.Value, error) {
+			v := reflect.New(t).Elem()
+			i64 := c.Int64(i)
+			if v.OverflowInt(i64) {
+				return v, fmt.Errorf("integer %d overflows %v", i64, t)
+			}
+			v.SetInt(i64)
+			return v, nil
+		}, nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return func(c *FunctionContext, i int) (reflect.Value, error) {
+			v := reflect.New(t).Elem()
+			i64 := c.Int64(i)
+			if i64 < 0 || v.OverflowUint(uint64(i64)) {
+				return v, fmt.Errorf("integer %d overflows %v", i64, t)
+			}
+			v.SetUint(uint64(i64))
+			return v, nil
+		}, nil
+	case reflect.Float32, reflect.Float64:
+		return func(c *FunctionContext, i int) (reflect.Value, error) {
+			return reflect.ValueOf(c.Double(i)).Convert(t), nil
+		}, nil
+	case reflect.String:
+		return func(c *FunctionContext, i int) (reflect.Value, error) {
+			return reflect.ValueOf(c.Text(i)).Convert(t), nil
+		}, nil
+	case reflect.Slice:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return func(c *FunctionContext, i int) (reflect.Value, error) {
+				return reflect.ValueOf(c.Blob(i)).Convert(t), nil
+			}, nil
+		}
+	case reflect.Interface:
+		if t.NumMethod() == 0 {
+			return func(c *FunctionContext, i int) (reflect.Value, error) {
+				v := reflect.New(t).Elem()
+				if value := c.Value(i); value != nil {
+					v.Set(reflect.ValueOf(value))
+				}
+				return v, nil
+			}, nil
+		}
+	case reflect.Ptr:
+		if k := t.Elem().Kind(); k != reflect.Slice && k != reflect.Interface && k != reflect.Ptr {
+			conv, err := argConverterOf(t.Elem())
+			if err != nil {
+				return nil, err
+			}
+			return func(c *FunctionContext, i int) (reflect.Value, error) {
+				if c.Type(i) == Null {
+					return reflect.Zero(t), nil
+				}
+				v, err := conv(c, i)
+				if err != nil {
+					return v, err
+				}
+				p := reflect.New(t.Elem())
+				p.Elem().Set(v)
+				return p, nil
+			}, nil
+		}
+	}
+	return nil, fmt.Errorf("unsupported argument type: %v", t)
+}
+
+// resultConverterOf returns a converter for the results of the function type t:
+// one value optionally followed by an error.
+func resultConverterOf(t reflect.Type) (resultConverter, error) {
+	switch {
+	case t.NumOut() == 1 && t.Out(0) != errorType:
+	case t.NumOut() == 2 && t.Out(1) == errorType:
+	default:
+		return nil, fmt.Errorf("expected one result optionally followed by an error but got %v", t)
+	}
+	conv, err := valueConverterOf(t.Out(0))
+	if err != nil {
+		return nil, err
+	}
+	return func(c *FunctionContext, out []reflect.Value) {
+		if len(out) == 2 && !out[1].IsNil() {
+			c.ResultError(out[1].Interface().(error).Error())
+			return
+		}
+		conv(c, out[0])
+	}, nil
+}
+
+func valueConverterOf(t reflect.Type) (valueConverter, error) {
+	switch t.Kind() {
+	case reflect.Bool:
+		return func(c *FunctionContext, v reflect.Value) {
+			c.ResultBool(v.Bool())
+		}, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return func(c *FunctionContext, v reflect.Value) {
+			c.ResultInt64(v.Int())
+		}, nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return func(c *FunctionContext, v reflect.Value) {
+			if u := v.Uint(); u > math.MaxInt64 {
+				c.ResultError(fmt.Sprintf("integer %d overflows int64", u))
+			} else {
+				c.ResultInt64(int64(u))
+			}
+		}, nil
+	case reflect.Float32, reflect.Float64:
+		return func(c *FunctionContext, v reflect.Value) {
+			c.ResultDouble(v.Float())
+		}, nil
+	case reflect.String:
+		return func(c *FunctionContext, v reflect.Value) {
+			c.ResultText(v.String())
+		}, nil
+	case reflect.Slice:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return func(c *FunctionContext, v reflect.Value) {
+				if v.IsNil() {
+					c.ResultNull()
+				} else {
+					c.ResultBlob(v.Bytes())
+				}
+			}, nil
+		}
+	case reflect.Interface:
+		if t.NumMethod() == 0 {
+			return func(c *FunctionContext, v reflect.Value) {
+				c.Result(v.Interface())
+			}, nil
+		}
+	case reflect.Ptr:
+		if k := t.Elem().Kind(); k != reflect.Slice && k != reflect.Interface && k != reflect.Ptr {
+			conv, err := valueConverterOf(t.Elem())
+			if err != nil {
+				return nil, err
+			}
+			return func(c *FunctionContext, v reflect.Value) {
+				if v.IsNil() {
+					c.ResultNull()
+				} else {
+					conv(c, v.Elem())
+				}
+			}, nil
+		}
+	}
+	return nil, fmt.Errorf("unsupported result type: %v", t)
+}
+
+// RegisterAggregator creates or redefines an SQL aggregate function implemented
+// by the objects returned by factory, a function like:
+//
+//	func() *percentile
+//
+// A fresh object is created for each group.
+// It must have a Step method, called for each row, and a Done method,
+// called once to compute the result:
+//
+//	func (p *percentile) Step(v float64, pct int64) error
+//	func (p *percentile) Done() (float64, error)
+//
+// Step may be variadic and may return an error.
+// Done must return one value, optionally followed by an error.
+// Arguments and result are converted like with RegisterFunc.
+// Cannot be used with Go >= 1.6 and cgocheck enabled.
+func (c *Conn) RegisterAggregator(name string, factory interface{}) error {
+	fv := reflect.ValueOf(factory)
+	if fv.Kind() != reflect.Func || fv.Type().NumIn() != 0 || fv.Type().NumOut() != 1 {
+		return fmt.Errorf("RegisterAggregator(%q): expected a factory function but got %T", name, factory)
+	}
+	at := fv.Type().Out(0)
+	stepMethod, ok := at.MethodByName("Step")
+	if !ok {
+		return fmt.Errorf("RegisterAggregator(%q): missing Step method in %v", name, at)
+	}
+	doneMethod, ok := at.MethodByName("Done")
+	if !ok {
+		return fmt.Errorf("RegisterAggregator(%q): missing Done method in %v", name, at)
+	}
+	// method types include the receiver when retrieved from a concrete type
+	recv := 1
+	if at.Kind() == reflect.Interface {
+		recv = 0
+	}
+	stepType, doneType := stepMethod.Type, doneMethod.Type
+	if stepType.NumOut() > 1 || stepType.NumOut() == 1 && stepType.Out(0) != errorType {
+		return fmt.Errorf("RegisterAggregator(%q): Step must return nothing or an error", name)
+	}
+	ins := make([]reflect.Type, stepType.NumIn()-recv)
+	for i := range ins {
+		ins[i] = stepType.In(i + recv)
+	}
+	stepArgsType := reflect.FuncOf(ins, nil, stepType.IsVariadic())
+	args, err := argConverters(stepArgsType)
+	if err != nil {
+		return fmt.Errorf("RegisterAggregator(%q): %s", name, err)
+	}
+	if doneType.NumIn() != recv {
+		return fmt.Errorf("RegisterAggregator(%q): Done must not have any argument", name)
+	}
+	outs := make([]reflect.Type, doneType.NumOut())
+	for i := range outs {
+		outs[i] = doneType.Out(i)
+	}
+	result, err := resultConverterOf(reflect.FuncOf(nil, outs, false))
+	if err != nil {
+		return fmt.Errorf("RegisterAggregator(%q): %s", name, err)
+	}
+	nArg := int32(len(ins))
+	if stepType.IsVariadic() {
+		nArg = -1
+	}
+	aggregator := func(ctx *AggregateContext) reflect.Value {
+		if a, ok := ctx.Aggregate.(reflect.Value); ok {
+			return a
+		}
+		a := fv.Call(nil)[0]
+		ctx.Aggregate = a
+		return a
+	}
+	step := func(ctx *AggregateContext, nArg int) {
+		in, err := callArgs(stepArgsType, args, &ctx.FunctionContext, nArg)
+		if err != nil {
+			ctx.ResultError(err.Error())
+			return
+		}
+		out := aggregator(ctx).Method(stepMethod.Index).Call(in)
+		if len(out) == 1 && !out[0].IsNil() {
+			ctx.ResultError(out[0].Interface().(error).Error())
+		}
+	}
+	final := func(ctx *AggregateContext) {
+		result(&ctx.FunctionContext, aggregator(ctx).Method(doneMethod.Index).Call(nil))
+		ctx.Aggregate = nil
+	}
+	// Done is also called on a fresh aggregator when there is no row
+	return c.createAggregateFunction(name, nArg, 0, nil, step, final, nil, true)
+}
@@ -5,9 +5,14 @@
 package sqlite_test
 
 import (
+	"errors"
+	"fmt"
+	"math"
 	"math/rand"
 	"os"
 	"regexp"
+	"sort"
+	"strings"
 	"testing"
 
 	"github.com/bmizerany/assert"
@@ -157,6 +162,19 @@ func TestSumFunction(t *testing.T) {
 	checkNoError(t, err, "couldn't execute statement: %s")
 	assert.Equal(t, 4, i)
 
+	// final is not called when there is no row
+	called := false
+	err = db.CreateAggregateFunction("mysum", 1, nil, sumStep, func(ctx *AggregateContext) {
+		called = true
+		ctx.ResultInt64(ctx.Aggregate.(int64))
+	}, nil)
+	checkNoError(t, err, "couldn't create function: %s")
+	var v interface{}
+	err = db.OneValue("SELECT mysum(i) FROM (SELECT 2 AS i) WHERE i > 2", &v)
+	checkNoError(t, err, "couldn't execute statement: %s")
+	assert.Equal(t, nil, v)
+	assert.T(t, !called, "unexpected final call")
+
 	err = db.CreateAggregateFunction("mysum", 1, nil, nil, nil, nil)
 	checkNoError(t, err, "couldn't unregister function: %s")
 }
@@ -222,3 +240,316 @@ func BenchmarkRegexp(b *testing.B) {
 		cs.Reset()
 	}
 }
+
+func sumInverse(ctx *AggregateContext, nArg int) {
+	nt := ctx.NumericType(0)
+	if nt == Integer || nt == Float {
+		if sum, ok := (ctx.Aggregate).(int64); ok {
+			ctx.Aggregate = sum - ctx.Int64(0)
+		}
+	}
+}
+
+func TestWindowFunction(t *testing.T) {
+	skipIfCgoCheckActive(t)
+
+	db := open(t)
+	defer checkClose(db, t)
+	err := db.CreateWindowFunction("mysum", 1, nil, sumStep, sumFinal, nil, sumInverse, nil)
+	assert.T(t, err != nil, "expected error without value function")
+	err = db.CreateWindowFunctionFlags("mysum", 1, FuncDeterministic, nil, sumStep, sumFinal, sumFinal, sumInverse, nil)
+	checkNoError(t, err, "couldn't create function: %s")
+
+	s, err := db.Prepare(`SELECT mysum(i) OVER (ORDER BY i ROWS BETWEEN 1 PRECEDING AND CURRENT ROW)
+		FROM (SELECT 1 AS i UNION ALL SELECT 2 UNION ALL SELECT 3 UNION ALL SELECT 4)`)
+	checkNoError(t, err, "couldn't prepare statement: %s")
+	defer checkFinalize(s, t)
+	var sums []int
+	err = s.Select(func(s *Stmt) error {
+		var i int
+		if err := s.Scan(&i); err != nil {
+			return err
+		}
+		sums = append(sums, i)
+		return nil
+	})
+	checkNoError(t, err, "couldn't execute statement: %s")
+	assert.Equal(t, []int{1, 3, 5, 7}, sums)
+
+	var i int
+	err = db.OneValue("SELECT mysum(i) FROM (SELECT 2 AS i UNION ALL SELECT 2)", &i)
+	checkNoError(t, err, "couldn't execute statement: %s")
+	assert.Equal(t, 4, i)
+
+	err = db.CreateWindowFunction("mysum", 1, nil, nil, nil, nil, nil, nil)
+	checkNoError(t, err, "couldn't unregister function: %s")
+}
+
+func TestRegisterFunc(t *testing.T) {
+	skipIfCgoCheckActive(t)
+
+	db := open(t)
+	defer checkClose(db, t)
+	err := db.RegisterFunc("scale", func(a int64, b float64) float64 {
+		return float64(a) * b
+	}, true)
+	checkNoError(t, err, "couldn't register function: %s")
+	err = db.RegisterFunc("joinstr", func(sep string, parts ...string) (string, error) {
+		if len(parts) == 0 {
+			return "", errors.New("nothing to join")
+		}
+		return strings.Join(parts, sep), nil
+	}, true)
+	checkNoError(t, err, "couldn't register function: %s")
+	err = db.RegisterFunc("typeof2", func(v interface{}) string {
+		return fmt.Sprintf("%T", v)
+	}, true)
+	checkNoError(t, err, "couldn't register function: %s")
+
+	var f float64
+	err = db.OneValue("SELECT scale(3, 1.5)", &f)
+	checkNoError(t, err, "couldn't execute statement: %s")
+	assert.Equal(t, 4.5, f)
+
+	var s string
+	err = db.OneValue("SELECT joinstr('-', 'a', 'b', 'c')", &s)
+	checkNoError(t, err, "couldn't execute statement: %s")
+	assert.Equal(t, "a-b-c", s)
+	err = db.OneValue("SELECT joinstr('-')", &s)
+	assert.T(t, err != nil, "expected error")
+	assert.T(t, strings.Contains(err.Error(), "nothing to join"), err.Error())
+
+	err = db.OneValue("SELECT typeof2(NULL) || typeof2(x'00')", &s)
+	checkNoError(t, err, "couldn't execute statement: %s")
+	assert.Equal(t, "<nil>[]uint8", s)
+
+	checkNoError(t, db.RegisterFunc("int8", func(i int8) int8 { return i }, true), "couldn't register function: %s")
+	checkNoError(t, db.RegisterFunc("uint", func(u uint) uint { return u }, true), "couldn't register function: %s")
+	checkNoError(t, db.RegisterFunc("maxuint64", func() uint64 { return math.MaxUint64 }, true), "couldn't register function: %s")
+	var i int
+	checkNoError(t, db.OneValue("SELECT int8(-128) + uint(3)", &i), "couldn't execute statement: %s")
+	assert.Equal(t, -125, i)
+	err = db.OneValue("SELECT int8(300)", &i)
+	assert.T(t, err != nil && strings.Contains(err.Error(), "overflows"), "expected overflow error")
+	err = db.OneValue("SELECT uint(-1)", &i)
+	assert.T(t, err != nil && strings.Contains(err.Error(), "overflows"), "expected overflow error")
+	err = db.OneValue("SELECT maxuint64()", &i)
+	assert.T(t, err != nil && strings.Contains(err.Error(), "overflows"), "expected overflow error")
+
+	// pointers distinguish NULL
+	err = db.RegisterFunc("nullable", func(i *int64) *string {
+		if i == nil {
+			return nil
+		}
+		s := fmt.Sprint(*i)
+		return &s
+	}, true)
+	checkNoError(t, err, "couldn't register function: %s")
+	var v interface{}
+	checkNoError(t, db.OneValue("SELECT nullable(NULL)", &v), "couldn't execute statement: %s")
+	assert.Equal(t, nil, v)
+	checkNoError(t, db.OneValue("SELECT nullable(0)", &v), "couldn't execute statement: %s")
+	assert.Equal(t, "0", v)
+
+	err = db.RegisterFunc("bad", func(c chan int) int { return 0 }, true)
+	assert.T(t, err != nil, "expected error with unsupported argument type")
+	err = db.RegisterFunc("bad", func() {}, true)
+	assert.T(t, err != nil, "expected error without result")
+}
+
+type median struct {
+	values []float64
+}
+
+func (m *median) Step(v float64) {
+	m.values = append(m.values, v)
+}
+
+func (m *median) Done() (float64, error) {
+	if len(m.values) == 0 {
+		return 0, errors.New("no value")
+	}
+	sort.Float64s(m.values)
+	n := len(m.values)
+	if n%2 == 0 {
+		return (m.values[n/2-1] + m.values[n/2]) / 2, nil
+	}
+	return m.values[n/2], nil
+}
+
+type concat struct {
+	parts []string
+}
+
+func (c *concat) Step(parts ...string) error {
+	if len(parts) == 0 {
+		return errors.New("missing argument")
+	}
+	c.parts = append(c.parts, parts...)
+	return nil
+}
+
+func (c *concat) Done() string {
+	return strings.Join(c.parts, ",")
+}
+
+func TestRegisterAggregator(t *testing.T) {
+	skipIfCgoCheckActive(t)
+
+	db := open(t)
+	defer checkClose(db, t)
+	err := db.RegisterAggregator("mymedian", func() *median { return new(median) })
+	checkNoError(t, err, "couldn't register aggregator: %s")
+	err = db.RegisterAggregator("myconcat", func() *concat { return new(concat) })
+	checkNoError(t, err, "couldn't register aggregator: %s")
+
+	s, err := db.Prepare(`SELECT g, mymedian(v), myconcat(v, g) FROM (SELECT 1 AS g, 3 AS v UNION ALL SELECT 1, 1
+		UNION ALL SELECT 2, 5 UNION ALL SELECT 2, 1 UNION ALL SELECT 2, 4 UNION ALL SELECT 1, 8) GROUP BY g ORDER BY g`)
+	checkNoError(t, err, "couldn't prepare statement: %s")
+	defer checkFinalize(s, t)
+	var medians []float64
+	var concats []string
+	err = s.Select(func(s *Stmt) error {
+		var g int
+		var m float64
+		var c string
+		if err := s.Scan(&g, &m, &c); err != nil {
+			return err
+		}
+		medians = append(medians, m)
+		concats = append(concats, c)
+		return nil
+	})
+	checkNoError(t, err, "couldn't execute statement: %s")
+	assert.Equal(t, []float64{3, 4}, medians)
+	assert.Equal(t, 2, len(concats))
+	assert.Equal(t, 6, len(strings.Split(concats[0], ",")))
+
+	var c string
+	err = db.OneValue("SELECT myconcat() FROM (SELECT 1)", &c)
+	assert.T(t, err != nil, "expected error")
+
+	// Done is called even when there is no row
+	checkNoError(t, db.FastExec("CREATE TABLE empty (v INTEGER)"), "%s")
+	var m float64
+	err = db.OneValue("SELECT mymedian(v) FROM empty", &m)
+	assert.T(t, err != nil, "expected 'no value' error")
+
+	err = db.RegisterAggregator("bad", func() int { return 0 })
+	assert.T(t, err != nil, "expected error without Step method")
+}
+
+func TestFunctionFlags(t *testing.T) {
+	skipIfCgoCheckActive(t)
+
+	db := open(t)
+	defer checkClose(db, t)
+	err := db.CreateScalarFunctionFlags("settag", 1, FuncDeterministic|FuncResultSubType, nil, func(ctx *ScalarContext, nArg int) {
+		ctx.ResultValue(0)
+		ctx.ResultSubType(42)
+	}, nil)
+	checkNoError(t, err, "couldn't create function: %s")
+	err = db.CreateScalarFunctionFlags("gettag", 1, FuncDeterministic|FuncSubType, nil, func(ctx *ScalarContext, nArg int) {
+		ctx.ResultInt64(int64(ctx.SubType(0)))
+	}, nil)
+	checkNoError(t, err, "couldn't create function: %s")
+
+	var i int
+	err = db.OneValue("SELECT gettag(settag('x'))", &i)
+	checkNoError(t, err, "couldn't execute statement: %s")
+	assert.Equal(t, 42, i)
+	err = db.OneValue("SELECT gettag('x')", &i)
+	checkNoError(t, err, "couldn't execute statement: %s")
+	assert.Equal(t, 0, i)
+
+	err = db.CreateScalarFunctionFlags("secret", 0, FuncDirectOnly, nil, func(ctx *ScalarContext, nArg int) {
+		ctx.ResultInt(1)
+	}, nil)
+	checkNoError(t, err, "couldn't create function: %s")
+	err = db.OneValue("SELECT secret()", &i)
+	checkNoError(t, err, "couldn't execute statement: %s")
+	err = db.FastExec("CREATE VIEW v AS SELECT secret() AS s")
+	checkNoError(t, err, "couldn't create view: %s")
+	err = db.OneValue("SELECT s FROM v", &i)
+	assert.T(t, err != nil, "expected error with direct-only function used in a view")
+}
+
+type document struct {
+	title string
+}
+
+func TestPointerPassing(t *testing.T) {
+	skipIfCgoCheckActive(t)
+
+	db := open(t)
+	defer checkClose(db, t)
+	err := db.CreateScalarFunction("parse", 1, false, nil, func(ctx *ScalarContext, nArg int) {
+		ctx.ResultPointer("document", &document{ctx.Text(0)})
+	}, nil)
+	checkNoError(t, err, "couldn't create function: %s")
+	err = db.CreateScalarFunction("title", 1, false, nil, func(ctx *ScalarContext, nArg int) {
+		if doc, ok := ctx.Pointer(0, "document").(*document); ok {
+			ctx.ResultText(doc.title)
+		} else {
+			ctx.ResultNull()
+		}
+	}, nil)
+	checkNoError(t, err, "couldn't create function: %s")
+
+	var title string
+	var null bool
+	err = db.OneValue("SELECT title(parse('hello'))", &title)
+	checkNoError(t, err, "couldn't execute statement: %s")
+	assert.Equal(t, "hello", title)
+
+	s, err := db.Prepare("SELECT title(?), ? IS NULL")
+	checkNoError(t, err, "couldn't prepare statement: %s")
+	defer checkFinalize(s, t)
+	err = s.BindPointer(1, "document", &document{"bound"})
+	checkNoError(t, err, "couldn't bind pointer: %s")
+	err = s.BindByIndex(2, Pointer{"document", &document{"other"}})
+	checkNoError(t, err, "couldn't bind pointer: %s")
+	assert.T(t, checkStep(t, s))
+	err = s.Scan(&title, &null)
+	checkNoError(t, err, "couldn't scan: %s")
+	assert.Equal(t, "bound", title)
+	assert.T(t, null, "expected pointer to be NULL for SQL")
+
+	// wrong type tag
+	s.Reset()
+	err = s.BindPointer(1, "other", &document{"bound"})
+	checkNoError(t, err, "couldn't bind pointer: %s")
+	var isNull bool
+	assert.T(t, checkStep(t, s))
+	_, isNull = s.ScanText(0)
+	assert.T(t, isNull, "expected NULL with wrong type tag")
+}
+
+func TestFunctionArgValue(t *testing.T) {
+	skipIfCgoCheckActive(t)
+
+	db := open(t)
+	defer checkClose(db, t)
+	var dup *Value
+	err := db.CreateScalarFunction("inspect", 1, false, nil, func(ctx *ScalarContext, nArg int) {
+		v := ctx.Arg(0)
+		if dup == nil {
+			dup = v.Dup()
+		}
+		ctx.ResultText(fmt.Sprintf("%s:%d:%t", v.Type(), len(v.Bytes()), v.FromBind()))
+	}, nil)
+	checkNoError(t, err, "couldn't create function: %s")
+
+	var s string
+	err = db.OneValue("SELECT inspect(x'010203')", &s)
+	checkNoError(t, err, "couldn't execute statement: %s")
+	assert.Equal(t, "Blob:3:false", s)
+	err = db.OneValue("SELECT inspect(?)", &s, "hello")
+	checkNoError(t, err, "couldn't execute statement: %s")
+	assert.Equal(t, "Text:5:true", s)
+
+	assert.T(t, dup != nil)
+	assert.Equal(t, []byte{1, 2, 3}, dup.Blob())
+	assert.Equal(t, []byte{1, 2, 3}, dup.Interface())
+	dup.Free()
+}
@@ -18,6 +18,16 @@ import (
 	"unsafe"
 )
 
+func init() {
+	columnMetadata = func(s *Stmt, index int) (*Column, error) {
+		table := s.ColumnTableName(index)
+		if table == "" { // expression
+			return nil, nil
+		}
+		return s.c.Column(s.ColumnDatabaseName(index), table, s.ColumnOriginName(index))
+	}
+}
+
 // Column extracts metadata about a column of a table (doesn't work with view).
 // Column.Cid and Column.DfltValue are left unspecified.
 // (See http://sqlite.org/c3ref/table_column_metadata.html)
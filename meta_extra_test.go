@@ -7,6 +7,7 @@
 package sqlite_test
 
 import (
+	"database/sql"
 	"testing"
 
 	"github.com/bmizerany/assert"
@@ -96,3 +97,25 @@ func TestColumnMetadataOnExpr(t *testing.T) {
 	affinity := s.ColumnTypeAffinity(0)
 	assert.Equal(t, None, affinity, "affinity")
 }
+
+func TestColumnTypeNullable(t *testing.T) {
+	db, err := sql.Open("sqlite3", ":memory:")
+	checkNoError(t, err, "Error opening database: %s")
+	defer db.Close()
+	_, err = db.Exec("CREATE TABLE test (id INTEGER PRIMARY KEY, name TEXT NOT NULL, descr TEXT)")
+	checkNoError(t, err, "Error creating table: %s")
+	rows, err := db.Query("SELECT name, descr, 1 FROM test")
+	checkNoError(t, err, "Error while querying: %s")
+	defer rows.Close()
+	types, err := rows.ColumnTypes()
+	checkNoError(t, err, "Error while getting column types: %s")
+
+	nullable, ok := types[0].Nullable()
+	assert.T(t, ok)
+	assert.T(t, !nullable, "NOT NULL column")
+	nullable, ok = types[1].Nullable()
+	assert.T(t, ok)
+	assert.T(t, nullable, "nullable column")
+	_, ok = types[2].Nullable()
+	assert.T(t, !ok, "unknown for expression")
+}
@@ -0,0 +1,41 @@
+// Copyright 2010 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+#include <sqlite3.h>
+
+// Go values are not passed to SQLite: only an handle allocated in C memory.
+static const char goPointerType[] = "gosqlite.Pointer";
+
+extern void goXPointerDestroy(sqlite3_uint64 h);
+
+static void cXPointerDestroy(void *p) {
+	goXPointerDestroy(*(sqlite3_uint64*)p);
+	sqlite3_free(p);
+}
+
+int goSqlite3BindPointer(sqlite3_stmt *stmt, int i, sqlite3_uint64 h) {
+	sqlite3_uint64 *p = sqlite3_malloc(sizeof(*p));
+	if (p == 0) {
+		goXPointerDestroy(h);
+		return SQLITE_NOMEM;
+	}
+	*p = h;
+	return sqlite3_bind_pointer(stmt, i, p, goPointerType, cXPointerDestroy);
+}
+
+void goSqlite3ResultPointer(sqlite3_context *ctx, sqlite3_uint64 h) {
+	sqlite3_uint64 *p = sqlite3_malloc(sizeof(*p));
+	if (p == 0) {
+		goXPointerDestroy(h);
+		sqlite3_result_error_nomem(ctx);
+		return;
+	}
+	*p = h;
+	sqlite3_result_pointer(ctx, p, goPointerType, cXPointerDestroy);
+}
+
+sqlite3_uint64 goSqlite3ValuePointer(sqlite3_value *v) {
+	sqlite3_uint64 *p = sqlite3_value_pointer(v, goPointerType);
+	return p ? *p : 0;
+}
@@ -0,0 +1,103 @@
+// Copyright 2010 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sqlite
+
+/*
+#include <sqlite3.h>
+
+int goSqlite3BindPointer(sqlite3_stmt *stmt, int i, sqlite3_uint64 h);
+void goSqlite3ResultPointer(sqlite3_context *ctx, sqlite3_uint64 h);
+sqlite3_uint64 goSqlite3ValuePointer(sqlite3_value *v);
+*/
+import "C"
+
+import (
+	"fmt"
+	"sync"
+)
+
+// Pointer is an opaque Go value passed between statements, functions and virtual tables
+// without serialization.
+// For SQL, a Pointer is a NULL value.
+// Only Go code, knowing the Type tag, can retrieve the Value.
+// Go values are never passed to SQLite (only an handle) so it can be used with cgocheck enabled.
+// (See http://sqlite.org/bindptr.html)
+type Pointer struct {
+	Type  string // Type tag
+	Value interface{}
+}
+
+var pointers = struct {
+	sync.Mutex
+	m    map[uint64]Pointer
+	next uint64
+}{m: make(map[uint64]Pointer)}
+
+func newPointerHandle(p Pointer) C.sqlite3_uint64 {
+	pointers.Lock()
+	defer pointers.Unlock()
+	pointers.next++
+	pointers.m[pointers.next] = p
+	return C.sqlite3_uint64(pointers.next)
+}
+
+func lookupPointer(h C.sqlite3_uint64) (Pointer, bool) {
+	pointers.Lock()
+	defer pointers.Unlock()
+	p, ok := pointers.m[uint64(h)]
+	return p, ok
+}
+
+//export goXPointerDestroy
+func goXPointerDestroy(h C.sqlite3_uint64) {
+	pointers.Lock()
+	delete(pointers.m, uint64(h))
+	pointers.Unlock()
+}
+
+// BindPointer binds an opaque Go value tagged with typ to the specified host parameter of the prepared statement.
+// The leftmost SQL parameter has an index of 1.
+// (See http://sqlite.org/c3ref/bind_blob.html)
+func (s *Stmt) BindPointer(index int, typ string, v interface{}) error {
+	rv := C.goSqlite3BindPointer(s.stmt, C.int(index), newPointerHandle(Pointer{typ, v}))
+	return s.error(rv, fmt.Sprintf("Stmt.BindPointer(%d, %q)", index, typ))
+}
+
+// ResultPointer sets the result of an SQL function to an opaque Go value tagged with typ.
+// (See sqlite3_result_pointer, http://sqlite.org/c3ref/result_blob.html)
+func (c *Context) ResultPointer(typ string, v interface{}) {
+	C.goSqlite3ResultPointer((*C.sqlite3_context)(c), newPointerHandle(Pointer{typ, v}))
+}
+
+// ResultPointer sets the result of an SQL function to an opaque Go value tagged with typ.
+func (c *FunctionContext) ResultPointer(typ string, v interface{}) {
+	c.sc.ResultPointer(typ, v)
+}
+
+// Pointer obtains the Go value tagged with typ passed as a SQL function parameter.
+// Returns nil if the parameter is not a Pointer or if its type tag does not match.
+// The leftmost value is number 0.
+// (See sqlite3_value_pointer, http://sqlite.org/c3ref/value_blob.html)
+func (c *FunctionContext) Pointer(i int, typ string) interface{} {
+	return c.Arg(i).Pointer(typ)
+}
+
+// Pointer obtains the Go value tagged with typ.
+// Returns nil if the value is not a Pointer or if its type tag does not match.
+// (See sqlite3_value_pointer, http://sqlite.org/c3ref/value_blob.html)
+func (v *Value) Pointer(typ string) interface{} {
+	if p, ok := v.pointer(); ok && p.Type == typ {
+		return p.Value
+	}
+	return nil
+}
+
+func (v *Value) pointer() (Pointer, bool) {
+	h := C.goSqlite3ValuePointer((*C.sqlite3_value)(v))
+	if h == 0 {
+		return Pointer{}, false
+	}
+	return lookupPointer(h)
+}
@@ -0,0 +1,13 @@
+// Copyright 2010 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// +build all
+
+#include <sqlite3.h>
+
+extern void goXPreUpdateHook(void *udp, sqlite3 *db, int op, char const *zDb, char const *zName, sqlite3_int64 iKey1, sqlite3_int64 iKey2);
+
+void* goSqlite3PreUpdateHook(sqlite3 *db, void *udp) {
+	return sqlite3_preupdate_hook(db, goXPreUpdateHook, udp);
+}
@@ -0,0 +1,99 @@
+// Copyright 2010 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// +build all
+// See SQLITE_ENABLE_PREUPDATE_HOOK (http://www.sqlite.org/compile.html)
+
+package sqlite
+
+/*
+#cgo CFLAGS: -DSQLITE_ENABLE_PREUPDATE_HOOK=1
+#include <sqlite3.h>
+
+void* goSqlite3PreUpdateHook(sqlite3 *db, void *udp);
+*/
+import "C"
+
+import (
+	"unsafe"
+)
+
+// PreUpdateData gives access to the row being modified.
+// It is only valid during the PreUpdateHook invocation.
+// (See http://sqlite.org/c3ref/preupdate_count.html)
+type PreUpdateData struct {
+	c         *Conn
+	Action    Action // Insert, Delete or Update
+	DbName    string
+	TableName string
+	OldRowID  int64 // rowid of the row before the change (Update and Delete)
+	NewRowID  int64 // rowid of the row after the change (Insert and Update)
+}
+
+// Count returns the number of columns in the row being modified.
+// (See sqlite3_preupdate_count, http://sqlite.org/c3ref/preupdate_count.html)
+func (d *PreUpdateData) Count() int {
+	return int(C.sqlite3_preupdate_count(d.c.db))
+}
+
+// Depth returns 0 for a direct change, 1 for a change made by a top-level trigger, ...
+// (See sqlite3_preupdate_depth, http://sqlite.org/c3ref/preupdate_count.html)
+func (d *PreUpdateData) Depth() int {
+	return int(C.sqlite3_preupdate_depth(d.c.db))
+}
+
+// Old returns the value of the i-th column before the change (Update and Delete only).
+// The leftmost column is number 0.
+// (See sqlite3_preupdate_old, http://sqlite.org/c3ref/preupdate_count.html)
+func (d *PreUpdateData) Old(i int) (*Value, error) {
+	var v *C.sqlite3_value
+	rv := C.sqlite3_preupdate_old(d.c.db, C.int(i), &v)
+	if rv != C.SQLITE_OK {
+		return nil, d.c.error(rv, "PreUpdateData.Old")
+	}
+	return (*Value)(v), nil
+}
+
+// New returns the value of the i-th column after the change (Insert and Update only).
+// The leftmost column is number 0.
+// (See sqlite3_preupdate_new, http://sqlite.org/c3ref/preupdate_count.html)
+func (d *PreUpdateData) New(i int) (*Value, error) {
+	var v *C.sqlite3_value
+	rv := C.sqlite3_preupdate_new(d.c.db, C.int(i), &v)
+	if rv != C.SQLITE_OK {
+		return nil, d.c.error(rv, "PreUpdateData.New")
+	}
+	return (*Value)(v), nil
+}
+
+// PreUpdateHook is the callback function signature.
+type PreUpdateHook func(udp interface{}, d *PreUpdateData)
+
+type sqlitePreUpdateHook struct {
+	f   PreUpdateHook
+	udp interface{}
+	c   *Conn
+}
+
+//export goXPreUpdateHook
+func goXPreUpdateHook(udp, db unsafe.Pointer, action int, dbName, tableName *C.char, oldRowID, newRowID C.sqlite3_int64) {
+	arg := (*sqlitePreUpdateHook)(udp)
+	arg.f(arg.udp, &PreUpdateData{arg.c, Action(action), C.GoString(dbName), C.GoString(tableName), int64(oldRowID), int64(newRowID)})
+}
+
+// PreUpdateHook registers a callback to be invoked prior to each INSERT, UPDATE and DELETE operation
+// on a rowid table (or a WITHOUT ROWID table).
+// Cannot be used with Go >= 1.6 and cgocheck enabled.
+// (See http://sqlite.org/c3ref/preupdate_count.html)
+func (c *Conn) PreUpdateHook(f PreUpdateHook, udp interface{}) {
+	if f == nil {
+		c.preUpdateHook = nil
+		C.sqlite3_preupdate_hook(c.db, nil, nil)
+		return
+	}
+	// To make sure it is not gced, keep a reference in the connection.
+	h := &sqlitePreUpdateHook{f, udp, c}
+	c.preUpdateHook = h
+	C.goSqlite3PreUpdateHook(c.db, unsafe.Pointer(h))
+}
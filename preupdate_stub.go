@@ -0,0 +1,10 @@
+// Copyright 2010 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// +build !all
+
+package sqlite
+
+// sqlitePreUpdateHook is only available with the "all" build tag (see preupdate.go).
+type sqlitePreUpdateHook struct{}
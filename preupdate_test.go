@@ -0,0 +1,58 @@
+// Copyright 2010 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// +build all
+
+package sqlite_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/bmizerany/assert"
+	. "github.com/gwenn/gosqlite"
+)
+
+func TestPreUpdateHook(t *testing.T) {
+	skipIfCgoCheckActive(t)
+
+	db := open(t)
+	defer checkClose(db, t)
+	err := db.FastExec(`CREATE TABLE test (name TEXT, rank INT);
+		INSERT INTO test VALUES ('a', 1);`)
+	checkNoError(t, err, "%s")
+
+	var changes []string
+	db.PreUpdateHook(func(udp interface{}, d *PreUpdateData) {
+		assert.Equal(t, 2, d.Count())
+		assert.Equal(t, 0, d.Depth())
+		var old, new string
+		if d.Action != Insert {
+			v, err := d.Old(0)
+			checkNoError(t, err, "couldn't get old value: %s")
+			old = v.Text()
+		}
+		if d.Action != Delete {
+			v, err := d.New(0)
+			checkNoError(t, err, "couldn't get new value: %s")
+			new = v.Text()
+		}
+		changes = append(changes, fmt.Sprintf("%d %s.%s %d>%d %q>%q", d.Action, d.DbName, d.TableName, d.OldRowID, d.NewRowID, old, new))
+	}, nil)
+
+	err = db.FastExec(`INSERT INTO test VALUES ('b', 2);
+		UPDATE test SET name = 'c' WHERE rowid = 1;
+		DELETE FROM test WHERE rowid = 2;`)
+	checkNoError(t, err, "%s")
+	assert.Equal(t, []string{
+		fmt.Sprintf("%d main.test 2>2 \"\">\"b\"", Insert),
+		fmt.Sprintf("%d main.test 1>1 \"a\">\"c\"", Update),
+		fmt.Sprintf("%d main.test 2>2 \"b\">\"\"", Delete),
+	}, changes)
+
+	db.PreUpdateHook(nil, nil)
+	err = db.FastExec("DELETE FROM test")
+	checkNoError(t, err, "%s")
+	assert.Equal(t, 3, len(changes))
+}
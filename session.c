@@ -0,0 +1,45 @@
+// Copyright 2010 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// +build all
+
+#include <sqlite3.h>
+
+extern int goXChangesetFilter(void *pCtx, const char *zTab);
+extern int goXChangesetConflict(void *pCtx, int eConflict, sqlite3_changeset_iter *p);
+
+int goSqlite3ChangesetApply(sqlite3 *db, int nChangeset, void *pChangeset, void *pCtx, int filter) {
+	return sqlite3changeset_apply(db, nChangeset, pChangeset, filter ? goXChangesetFilter : 0, goXChangesetConflict, pCtx);
+}
+
+extern int goXInput(void *pIn, void *pData, int *pnData);
+extern int goXOutput(void *pOut, void *pData, int nData);
+
+static int cXOutput(void *pOut, const void *pData, int nData) {
+	return goXOutput(pOut, (void *)pData, nData);
+}
+
+int goSqlite3ChangesetApplyStrm(sqlite3 *db, void *pIn, void *pCtx, int filter) {
+	return sqlite3changeset_apply_strm(db, goXInput, pIn, filter ? goXChangesetFilter : 0, goXChangesetConflict, pCtx);
+}
+
+int goSqlite3ChangesetStartStrm(sqlite3_changeset_iter **pp, void *pIn) {
+	return sqlite3changeset_start_strm(pp, goXInput, pIn);
+}
+
+int goSqlite3SessionChangesetStrm(sqlite3_session *pSession, void *pOut) {
+	return sqlite3session_changeset_strm(pSession, cXOutput, pOut);
+}
+
+int goSqlite3SessionPatchsetStrm(sqlite3_session *pSession, void *pOut) {
+	return sqlite3session_patchset_strm(pSession, cXOutput, pOut);
+}
+
+int goSqlite3ChangesetInvertStrm(void *pIn, void *pOut) {
+	return sqlite3changeset_invert_strm(goXInput, pIn, cXOutput, pOut);
+}
+
+int goSqlite3ChangesetConcatStrm(void *pInA, void *pInB, void *pOut) {
+	return sqlite3changeset_concat_strm(goXInput, pInA, goXInput, pInB, cXOutput, pOut);
+}
@@ -0,0 +1,530 @@
+// Copyright 2010 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// +build all
+// See SQLITE_ENABLE_SESSION (http://www.sqlite.org/compile.html)
+
+package sqlite
+
+/*
+#cgo CFLAGS: -DSQLITE_ENABLE_SESSION=1 -DSQLITE_ENABLE_PREUPDATE_HOOK=1
+#include <sqlite3.h>
+#include <stdlib.h>
+
+int goSqlite3ChangesetApply(sqlite3 *db, int nChangeset, void *pChangeset, void *pCtx, int filter);
+int goSqlite3ChangesetApplyStrm(sqlite3 *db, void *pIn, void *pCtx, int filter);
+int goSqlite3ChangesetStartStrm(sqlite3_changeset_iter **pp, void *pIn);
+int goSqlite3SessionChangesetStrm(sqlite3_session *pSession, void *pOut);
+int goSqlite3SessionPatchsetStrm(sqlite3_session *pSession, void *pOut);
+int goSqlite3ChangesetInvertStrm(void *pIn, void *pOut);
+int goSqlite3ChangesetConcatStrm(void *pInA, void *pInB, void *pOut);
+*/
+import "C"
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"unsafe"
+)
+
+// Session records changes made to tables of a database
+// and produces changesets or patchsets.
+// A Session must be deleted before the associated Conn is closed.
+// (See http://sqlite.org/sessionintro.html)
+type Session struct {
+	c *Conn
+	s *C.sqlite3_session
+}
+
+// CreateSession creates a new session object attached to the specified database ("main", "temp", ...).
+// (See http://sqlite.org/session/sqlite3session_create.html)
+func (c *Conn) CreateSession(dbName string) (*Session, error) {
+	zDb := C.CString(dbName)
+	defer C.free(unsafe.Pointer(zDb))
+	var s *C.sqlite3_session
+	rv := C.sqlite3session_create(c.db, zDb, &s)
+	if rv != C.SQLITE_OK {
+		return nil, c.error(rv, fmt.Sprintf("Conn.CreateSession(%q)", dbName))
+	}
+	return &Session{c, s}, nil
+}
+
+// Delete deletes the session object.
+// (See http://sqlite.org/session/sqlite3session_delete.html)
+func (s *Session) Delete() error {
+	if s == nil {
+		return errors.New("nil sqlite session")
+	}
+	if s.s == nil {
+		return nil
+	}
+	C.sqlite3session_delete(s.s)
+	s.s = nil
+	return nil
+}
+
+// Attach attaches a table to the session.
+// All tables are attached when table is empty.
+// (See http://sqlite.org/session/sqlite3session_attach.html)
+func (s *Session) Attach(table string) error {
+	var zTab *C.char
+	if len(table) > 0 {
+		zTab = C.CString(table)
+		defer C.free(unsafe.Pointer(zTab))
+	}
+	return s.c.error(C.sqlite3session_attach(s.s, zTab), fmt.Sprintf("Session.Attach(%q)", table))
+}
+
+// Enable enables or disables the recording of changes.
+// (See http://sqlite.org/session/sqlite3session_enable.html)
+func (s *Session) Enable(b bool) {
+	C.sqlite3session_enable(s.s, btocint(b))
+}
+
+// IsEnabled tells if the recording of changes is enabled.
+// (See http://sqlite.org/session/sqlite3session_enable.html)
+func (s *Session) IsEnabled() bool {
+	return C.sqlite3session_enable(s.s, -1) != 0
+}
+
+// SetIndirect sets the indirect change flag.
+// (See http://sqlite.org/session/sqlite3session_indirect.html)
+func (s *Session) SetIndirect(b bool) {
+	C.sqlite3session_indirect(s.s, btocint(b))
+}
+
+// IsEmpty tells if no change has been recorded.
+// (See http://sqlite.org/session/sqlite3session_isempty.html)
+func (s *Session) IsEmpty() bool {
+	return C.sqlite3session_isempty(s.s) != 0
+}
+
+// Diff loads the differences between table in fromDb and the same table in the session database.
+// (See http://sqlite.org/session/sqlite3session_diff.html)
+func (s *Session) Diff(fromDb, table string) error {
+	zFromDb := C.CString(fromDb)
+	defer C.free(unsafe.Pointer(zFromDb))
+	zTab := C.CString(table)
+	defer C.free(unsafe.Pointer(zTab))
+	var zErr *C.char
+	rv := C.sqlite3session_diff(s.s, zFromDb, zTab, &zErr)
+	if rv != C.SQLITE_OK {
+		if zErr != nil {
+			defer C.sqlite3_free(unsafe.Pointer(zErr))
+			return s.c.specificError("Session.Diff(%q, %q): %s", fromDb, table, C.GoString(zErr))
+		}
+		return s.c.error(rv, fmt.Sprintf("Session.Diff(%q, %q)", fromDb, table))
+	}
+	return nil
+}
+
+// Changeset returns the changeset of all changes recorded.
+// (See http://sqlite.org/session/sqlite3session_changeset.html)
+func (s *Session) Changeset() ([]byte, error) {
+	var n C.int
+	var p unsafe.Pointer
+	rv := C.sqlite3session_changeset(s.s, &n, &p)
+	if rv != C.SQLITE_OK {
+		return nil, s.c.error(rv, "Session.Changeset")
+	}
+	return sqliteBytes(n, p), nil
+}
+
+// Patchset returns the patchset of all changes recorded.
+// A patchset is a more compact changeset without original values.
+// (See http://sqlite.org/session/sqlite3session_patchset.html)
+func (s *Session) Patchset() ([]byte, error) {
+	var n C.int
+	var p unsafe.Pointer
+	rv := C.sqlite3session_patchset(s.s, &n, &p)
+	if rv != C.SQLITE_OK {
+		return nil, s.c.error(rv, "Session.Patchset")
+	}
+	return sqliteBytes(n, p), nil
+}
+
+// sqliteBytes copies and frees a buffer allocated by SQLite.
+func sqliteBytes(n C.int, p unsafe.Pointer) []byte {
+	if p == nil {
+		return nil
+	}
+	defer C.sqlite3_free(p)
+	return C.GoBytes(p, n)
+}
+
+// changesetPointer returns a pointer usable during a C call.
+func changesetPointer(changeset []byte) unsafe.Pointer {
+	if len(changeset) == 0 {
+		return nil
+	}
+	return unsafe.Pointer(&changeset[0])
+}
+
+// InvertChangeset returns the inverse of changeset.
+// (See http://sqlite.org/session/sqlite3changeset_invert.html)
+func InvertChangeset(changeset []byte) ([]byte, error) {
+	var n C.int
+	var p unsafe.Pointer
+	rv := C.sqlite3changeset_invert(C.int(len(changeset)), changesetPointer(changeset), &n, &p)
+	if rv != C.SQLITE_OK {
+		return nil, Errno(rv)
+	}
+	return sqliteBytes(n, p), nil
+}
+
+// ConcatChangesets returns the concatenation of two changesets.
+// (See http://sqlite.org/session/sqlite3changeset_concat.html)
+func ConcatChangesets(a, b []byte) ([]byte, error) {
+	var n C.int
+	var p unsafe.Pointer
+	rv := C.sqlite3changeset_concat(C.int(len(a)), changesetPointer(a), C.int(len(b)), changesetPointer(b), &n, &p)
+	if rv != C.SQLITE_OK {
+		return nil, Errno(rv)
+	}
+	return sqliteBytes(n, p), nil
+}
+
+// ConflictType enumerates the reasons why the conflict handler is invoked
+// (See http://sqlite.org/session/c_changeset_conflict.html)
+type ConflictType int32
+
+// Conflict types
+const (
+	ChangesetData       ConflictType = C.SQLITE_CHANGESET_DATA
+	ChangesetNotFound   ConflictType = C.SQLITE_CHANGESET_NOTFOUND
+	ChangesetConflict   ConflictType = C.SQLITE_CHANGESET_CONFLICT
+	ChangesetConstraint ConflictType = C.SQLITE_CHANGESET_CONSTRAINT
+	ChangesetForeignKey ConflictType = C.SQLITE_CHANGESET_FOREIGN_KEY
+)
+
+// ConflictAction enumerates the values returned by the conflict handler
+// (See http://sqlite.org/session/c_changeset_abort.html)
+type ConflictAction int32
+
+// Conflict actions
+const (
+	ChangesetOmit    ConflictAction = C.SQLITE_CHANGESET_OMIT
+	ChangesetReplace ConflictAction = C.SQLITE_CHANGESET_REPLACE
+	ChangesetAbort   ConflictAction = C.SQLITE_CHANGESET_ABORT
+)
+
+// ConflictHandler is the callback function signature.
+// iter points to the conflicting change.
+// It is owned by SQLite and only valid during the callback: it must not be closed nor advanced.
+type ConflictHandler func(t ConflictType, iter *ChangesetIter) ConflictAction
+
+// ChangesetFilter is the callback function signature.
+// Changes to table are ignored when it returns false.
+type ChangesetFilter func(table string) bool
+
+type sqliteChangesetApply struct {
+	filter   ChangesetFilter
+	conflict ConflictHandler
+}
+
+//export goXChangesetFilter
+func goXChangesetFilter(pCtx unsafe.Pointer, zTab *C.char) C.int {
+	arg := (*sqliteChangesetApply)(pCtx)
+	return btocint(arg.filter(C.GoString(zTab)))
+}
+
+//export goXChangesetConflict
+func goXChangesetConflict(pCtx unsafe.Pointer, eConflict C.int, p *C.sqlite3_changeset_iter) C.int {
+	arg := (*sqliteChangesetApply)(pCtx)
+	if arg.conflict == nil {
+		return C.SQLITE_CHANGESET_ABORT
+	}
+	return C.int(arg.conflict(ConflictType(eConflict), &ChangesetIter{it: p, borrowed: true}))
+}
+
+// ApplyChangeset applies a changeset (or a patchset) to the database.
+// When conflict is nil, the first conflict aborts the whole changeset.
+// Cannot be used with Go >= 1.6 and cgocheck enabled.
+// (See http://sqlite.org/session/sqlite3changeset_apply.html)
+func (c *Conn) ApplyChangeset(changeset []byte, filter ChangesetFilter, conflict ConflictHandler) error {
+	arg := &sqliteChangesetApply{filter, conflict}
+	rv := C.goSqlite3ChangesetApply(c.db, C.int(len(changeset)), changesetPointer(changeset), unsafe.Pointer(arg), btocint(filter != nil))
+	return c.error(rv, "Conn.ApplyChangeset")
+}
+
+// ChangesetIter points to a change in a changeset.
+// (See http://sqlite.org/session/changeset_iter.html)
+type ChangesetIter struct {
+	it       *C.sqlite3_changeset_iter
+	data     unsafe.Pointer      // copy of the changeset in C memory
+	in       *sqliteStreamReader // streamed changeset
+	borrowed bool                // iterator owned by SQLite (see ConflictHandler)
+}
+
+// NewChangesetIter creates an iterator over the changes of changeset (or patchset).
+// The iterator must be closed.
+// (See http://sqlite.org/session/sqlite3changeset_start.html)
+func NewChangesetIter(changeset []byte) (*ChangesetIter, error) {
+	// changeset is copied because the iterator keeps a reference to it between calls
+	var data unsafe.Pointer
+	if len(changeset) > 0 {
+		data = C.CBytes(changeset)
+	}
+	var it *C.sqlite3_changeset_iter
+	rv := C.sqlite3changeset_start(&it, C.int(len(changeset)), data)
+	if rv != C.SQLITE_OK {
+		C.free(data)
+		return nil, Errno(rv)
+	}
+	return &ChangesetIter{it: it, data: data}, nil
+}
+
+// NewChangesetIterStream creates an iterator over the changes read from r.
+// The iterator must be closed.
+// Cannot be used with Go >= 1.6 and cgocheck enabled.
+// (See http://sqlite.org/session/sqlite3changeset_start.html)
+func NewChangesetIterStream(r io.Reader) (*ChangesetIter, error) {
+	in := &sqliteStreamReader{r: r}
+	var it *C.sqlite3_changeset_iter
+	rv := C.goSqlite3ChangesetStartStrm(&it, unsafe.Pointer(in))
+	if rv != C.SQLITE_OK {
+		return nil, in.error(rv)
+	}
+	return &ChangesetIter{it: it, in: in}, nil
+}
+
+// Next advances the iterator to the next change.
+// Returns false when there is no more change.
+// (See http://sqlite.org/session/sqlite3changeset_next.html)
+func (it *ChangesetIter) Next() (bool, error) {
+	if it.borrowed {
+		return false, ErrMisuse
+	}
+	rv := C.sqlite3changeset_next(it.it)
+	if rv == C.SQLITE_ROW {
+		return true, nil
+	} else if rv == C.SQLITE_DONE {
+		return false, nil
+	}
+	if it.in != nil {
+		return false, it.in.error(rv)
+	}
+	return false, Errno(rv)
+}
+
+// Close finalizes the iterator.
+// It does nothing for the iterator passed to a ConflictHandler.
+// (See http://sqlite.org/session/sqlite3changeset_finalize.html)
+func (it *ChangesetIter) Close() error {
+	if it == nil {
+		return errors.New("nil sqlite changeset iterator")
+	}
+	if it.it == nil || it.borrowed {
+		return nil
+	}
+	rv := C.sqlite3changeset_finalize(it.it)
+	it.it = nil
+	if it.data != nil {
+		C.free(it.data)
+		it.data = nil
+	}
+	if rv != C.SQLITE_OK {
+		return Errno(rv)
+	}
+	return nil
+}
+
+// PK returns, for each column of the current change table, true if it is part of the primary key.
+// (See http://sqlite.org/session/sqlite3changeset_pk.html)
+func (it *ChangesetIter) PK() ([]bool, error) {
+	var abPK *C.uchar
+	var n C.int
+	rv := C.sqlite3changeset_pk(it.it, &abPK, &n)
+	if rv != C.SQLITE_OK {
+		return nil, Errno(rv)
+	}
+	pk := make([]bool, int(n))
+	for i, b := range (*[1 << 20]C.uchar)(unsafe.Pointer(abPK))[:n:n] {
+		pk[i] = b != 0
+	}
+	return pk, nil
+}
+
+// Op returns the table name, the number of columns, the action (Insert, Delete or Update)
+// and the indirect flag of the current change.
+// (See http://sqlite.org/session/sqlite3changeset_op.html)
+func (it *ChangesetIter) Op() (table string, nCol int, action Action, indirect bool, err error) {
+	var zTab *C.char
+	var n, op, ind C.int
+	rv := C.sqlite3changeset_op(it.it, &zTab, &n, &op, &ind)
+	if rv != C.SQLITE_OK {
+		return "", 0, 0, false, Errno(rv)
+	}
+	return C.GoString(zTab), int(n), Action(op), ind != 0, nil
+}
+
+// Old returns the original value of the i-th column (Update and Delete only).
+// nil is returned when the column is not modified by an Update.
+// (See http://sqlite.org/session/sqlite3changeset_old.html)
+func (it *ChangesetIter) Old(i int) (*Value, error) {
+	var v *C.sqlite3_value
+	rv := C.sqlite3changeset_old(it.it, C.int(i), &v)
+	if rv != C.SQLITE_OK {
+		return nil, Errno(rv)
+	}
+	return (*Value)(v), nil
+}
+
+// New returns the new value of the i-th column (Insert and Update only).
+// nil is returned when the column is not modified by an Update.
+// (See http://sqlite.org/session/sqlite3changeset_new.html)
+func (it *ChangesetIter) New(i int) (*Value, error) {
+	var v *C.sqlite3_value
+	rv := C.sqlite3changeset_new(it.it, C.int(i), &v)
+	if rv != C.SQLITE_OK {
+		return nil, Errno(rv)
+	}
+	return (*Value)(v), nil
+}
+
+// Conflict returns the conflicting row value of the i-th column.
+// Only valid in a ConflictHandler invoked with ChangesetData or ChangesetConflict.
+// (See http://sqlite.org/session/sqlite3changeset_conflict.html)
+func (it *ChangesetIter) Conflict(i int) (*Value, error) {
+	var v *C.sqlite3_value
+	rv := C.sqlite3changeset_conflict(it.it, C.int(i), &v)
+	if rv != C.SQLITE_OK {
+		return nil, Errno(rv)
+	}
+	return (*Value)(v), nil
+}
+
+// FKConflicts returns the number of foreign key constraint violations.
+// Only valid in a ConflictHandler invoked with ChangesetForeignKey.
+// (See http://sqlite.org/session/sqlite3changeset_fk_conflicts.html)
+func (it *ChangesetIter) FKConflicts() (int, error) {
+	var n C.int
+	rv := C.sqlite3changeset_fk_conflicts(it.it, &n)
+	if rv != C.SQLITE_OK {
+		return 0, Errno(rv)
+	}
+	return int(n), nil
+}
+
+type sqliteStreamReader struct {
+	r   io.Reader
+	err error
+}
+
+func (in *sqliteStreamReader) error(rv C.int) error {
+	if in.err != nil {
+		return in.err
+	}
+	return Errno(rv)
+}
+
+//export goXInput
+func goXInput(pIn, pData unsafe.Pointer, pnData *C.int) C.int {
+	in := (*sqliteStreamReader)(pIn)
+	n := int(*pnData)
+	if in.err != nil || n == 0 {
+		*pnData = 0
+		return C.SQLITE_OK
+	}
+	n, err := io.ReadFull(in.r, (*[1 << 30]byte)(pData)[:n:n])
+	*pnData = C.int(n)
+	if err == io.EOF || err == io.ErrUnexpectedEOF {
+		return C.SQLITE_OK
+	} else if err != nil {
+		in.err = err
+		return C.SQLITE_IOERR
+	}
+	return C.SQLITE_OK
+}
+
+type sqliteStreamWriter struct {
+	w   io.Writer
+	err error
+}
+
+func (out *sqliteStreamWriter) error(rv C.int) error {
+	if out.err != nil {
+		return out.err
+	}
+	return Errno(rv)
+}
+
+//export goXOutput
+func goXOutput(pOut, pData unsafe.Pointer, nData C.int) C.int {
+	out := (*sqliteStreamWriter)(pOut)
+	if nData == 0 {
+		return C.SQLITE_OK
+	}
+	if _, err := out.w.Write((*[1 << 30]byte)(pData)[:nData:nData]); err != nil {
+		out.err = err
+		return C.SQLITE_IOERR
+	}
+	return C.SQLITE_OK
+}
+
+// ChangesetStream writes the changeset of all changes recorded to w.
+// Cannot be used with Go >= 1.6 and cgocheck enabled.
+// (See http://sqlite.org/session/sqlite3session_changeset_strm.html)
+func (s *Session) ChangesetStream(w io.Writer) error {
+	out := &sqliteStreamWriter{w: w}
+	if rv := C.goSqlite3SessionChangesetStrm(s.s, unsafe.Pointer(out)); rv != C.SQLITE_OK {
+		return out.error(rv)
+	}
+	return nil
+}
+
+// PatchsetStream writes the patchset of all changes recorded to w.
+// Cannot be used with Go >= 1.6 and cgocheck enabled.
+// (See http://sqlite.org/session/sqlite3session_changeset_strm.html)
+func (s *Session) PatchsetStream(w io.Writer) error {
+	out := &sqliteStreamWriter{w: w}
+	if rv := C.goSqlite3SessionPatchsetStrm(s.s, unsafe.Pointer(out)); rv != C.SQLITE_OK {
+		return out.error(rv)
+	}
+	return nil
+}
+
+// ApplyChangesetStream applies the changeset (or patchset) read from r to the database.
+// When conflict is nil, the first conflict aborts the whole changeset.
+// Cannot be used with Go >= 1.6 and cgocheck enabled.
+// (See http://sqlite.org/session/sqlite3changeset_apply_strm.html)
+func (c *Conn) ApplyChangesetStream(r io.Reader, filter ChangesetFilter, conflict ConflictHandler) error {
+	in, arg := &sqliteStreamReader{r: r}, &sqliteChangesetApply{filter, conflict}
+	rv := C.goSqlite3ChangesetApplyStrm(c.db, unsafe.Pointer(in), unsafe.Pointer(arg), btocint(filter != nil))
+	if rv != C.SQLITE_OK && in.err != nil {
+		return in.err
+	}
+	return c.error(rv, "Conn.ApplyChangesetStream")
+}
+
+// InvertChangesetStream writes the inverse of the changeset read from r to w.
+// Cannot be used with Go >= 1.6 and cgocheck enabled.
+// (See http://sqlite.org/session/sqlite3changeset_invert_strm.html)
+func InvertChangesetStream(r io.Reader, w io.Writer) error {
+	in, out := &sqliteStreamReader{r: r}, &sqliteStreamWriter{w: w}
+	if rv := C.goSqlite3ChangesetInvertStrm(unsafe.Pointer(in), unsafe.Pointer(out)); rv != C.SQLITE_OK {
+		if in.err != nil {
+			return in.err
+		}
+		return out.error(rv)
+	}
+	return nil
+}
+
+// ConcatChangesetsStream writes the concatenation of the changesets read from a and b to w.
+// Cannot be used with Go >= 1.6 and cgocheck enabled.
+// (See http://sqlite.org/session/sqlite3changeset_concat_strm.html)
+func ConcatChangesetsStream(a, b io.Reader, w io.Writer) error {
+	inA, inB, out := &sqliteStreamReader{r: a}, &sqliteStreamReader{r: b}, &sqliteStreamWriter{w: w}
+	if rv := C.goSqlite3ChangesetConcatStrm(unsafe.Pointer(inA), unsafe.Pointer(inB), unsafe.Pointer(out)); rv != C.SQLITE_OK {
+		if inA.err != nil {
+			return inA.err
+		} else if inB.err != nil {
+			return inB.err
+		}
+		return out.error(rv)
+	}
+	return nil
+}
@@ -0,0 +1,253 @@
+// Copyright 2010 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// +build all
+
+package sqlite_test
+
+import (
+	"bytes"
+	"testing"
+	"testing/iotest"
+
+	"github.com/bmizerany/assert"
+	. "github.com/gwenn/gosqlite"
+)
+
+const sessionSchema = `CREATE TABLE test (id INTEGER PRIMARY KEY, name TEXT);
+	INSERT INTO test VALUES (1, 'a'), (2, 'b');`
+
+func selectTestNames(t *testing.T, db *Conn) map[int]string {
+	s, err := db.Prepare("SELECT id, name FROM test")
+	checkNoError(t, err, "couldn't prepare statement: %s")
+	defer checkFinalize(s, t)
+	names := make(map[int]string)
+	err = s.Select(func(s *Stmt) error {
+		var id int
+		var name string
+		if err := s.Scan(&id, &name); err != nil {
+			return err
+		}
+		names[id] = name
+		return nil
+	})
+	checkNoError(t, err, "couldn't select: %s")
+	return names
+}
+
+func recordChangeset(t *testing.T, db *Conn, sql string) []byte {
+	session, err := db.CreateSession("main")
+	checkNoError(t, err, "couldn't create session: %s")
+	defer session.Delete()
+	checkNoError(t, session.Attach(""), "couldn't attach session: %s")
+	assert.T(t, session.IsEnabled())
+	assert.T(t, session.IsEmpty())
+
+	checkNoError(t, db.FastExec(sql), "%s")
+	assert.T(t, !session.IsEmpty())
+	changeset, err := session.Changeset()
+	checkNoError(t, err, "couldn't get changeset: %s")
+	patchset, err := session.Patchset()
+	checkNoError(t, err, "couldn't get patchset: %s")
+	assert.T(t, len(patchset) > 0 && len(patchset) <= len(changeset))
+	return changeset
+}
+
+func TestSession(t *testing.T) {
+	skipIfCgoCheckActive(t)
+
+	src := open(t)
+	defer checkClose(src, t)
+	dst := open(t)
+	defer checkClose(dst, t)
+	checkNoError(t, src.FastExec(sessionSchema), "%s")
+	checkNoError(t, dst.FastExec(sessionSchema), "%s")
+
+	changeset := recordChangeset(t, src, `INSERT INTO test VALUES (3, 'c');
+		UPDATE test SET name = 'B' WHERE id = 2;
+		DELETE FROM test WHERE id = 1;`)
+
+	checkNoError(t, dst.ApplyChangeset(changeset, nil, nil), "couldn't apply changeset: %s")
+	assert.Equal(t, map[int]string{2: "B", 3: "c"}, selectTestNames(t, dst))
+
+	inverse, err := InvertChangeset(changeset)
+	checkNoError(t, err, "couldn't invert changeset: %s")
+	checkNoError(t, dst.ApplyChangeset(inverse, nil, nil), "couldn't apply changeset: %s")
+	assert.Equal(t, map[int]string{1: "a", 2: "b"}, selectTestNames(t, dst))
+
+	other := recordChangeset(t, src, "INSERT INTO test VALUES (4, 'd')")
+	concat, err := ConcatChangesets(changeset, other)
+	checkNoError(t, err, "couldn't concat changesets: %s")
+	checkNoError(t, dst.ApplyChangeset(concat, func(table string) bool {
+		return table == "test"
+	}, nil), "couldn't apply changeset: %s")
+	assert.Equal(t, map[int]string{2: "B", 3: "c", 4: "d"}, selectTestNames(t, dst))
+}
+
+func TestSessionConflict(t *testing.T) {
+	skipIfCgoCheckActive(t)
+
+	src := open(t)
+	defer checkClose(src, t)
+	dst := open(t)
+	defer checkClose(dst, t)
+	checkNoError(t, src.FastExec(sessionSchema), "%s")
+	checkNoError(t, dst.FastExec(sessionSchema), "%s")
+	checkNoError(t, dst.FastExec("UPDATE test SET name = 'x' WHERE id = 2"), "%s")
+
+	changeset := recordChangeset(t, src, `INSERT INTO test VALUES (3, 'c');
+		UPDATE test SET name = 'B' WHERE id = 2;`)
+
+	// abort by default
+	err := dst.ApplyChangeset(changeset, nil, nil)
+	assert.T(t, err != nil, "expected conflict error")
+	assert.Equal(t, map[int]string{1: "a", 2: "x"}, selectTestNames(t, dst))
+
+	var conflicts []ConflictType
+	err = dst.ApplyChangeset(changeset, nil, func(ct ConflictType, iter *ChangesetIter) ConflictAction {
+		conflicts = append(conflicts, ct)
+		table, nCol, action, _, err := iter.Op()
+		checkNoError(t, err, "couldn't get change: %s")
+		assert.Equal(t, "test", table)
+		assert.Equal(t, 2, nCol)
+		assert.Equal(t, Update, action)
+		v, err := iter.Conflict(1)
+		checkNoError(t, err, "couldn't get conflicting value: %s")
+		assert.Equal(t, "x", v.Text())
+		v, err = iter.Old(1)
+		checkNoError(t, err, "couldn't get old value: %s")
+		assert.Equal(t, "b", v.Text())
+		// the iterator is owned by SQLite
+		_, err = iter.Next()
+		assert.Equal(t, ErrMisuse, err)
+		checkNoError(t, iter.Close(), "couldn't close iterator: %s")
+		return ChangesetReplace
+	})
+	checkNoError(t, err, "couldn't apply changeset: %s")
+	assert.Equal(t, []ConflictType{ChangesetData}, conflicts)
+	assert.Equal(t, map[int]string{1: "a", 2: "B", 3: "c"}, selectTestNames(t, dst))
+}
+
+type change struct {
+	table  string
+	action Action
+	old    []interface{}
+	new    []interface{}
+}
+
+func readChanges(t *testing.T, iter *ChangesetIter) []change {
+	var changes []change
+	for {
+		ok, err := iter.Next()
+		checkNoError(t, err, "couldn't iterate changeset: %s")
+		if !ok {
+			break
+		}
+		table, nCol, action, _, err := iter.Op()
+		checkNoError(t, err, "couldn't get change: %s")
+		pk, err := iter.PK()
+		checkNoError(t, err, "couldn't get primary key: %s")
+		assert.Equal(t, []bool{true, false}, pk)
+		c := change{table: table, action: action}
+		for i := 0; i < nCol; i++ {
+			if action != Insert {
+				v, err := iter.Old(i)
+				checkNoError(t, err, "couldn't get old value: %s")
+				if v != nil {
+					c.old = append(c.old, v.Interface())
+				} else {
+					c.old = append(c.old, nil)
+				}
+			}
+			if action != Delete {
+				v, err := iter.New(i)
+				checkNoError(t, err, "couldn't get new value: %s")
+				if v != nil {
+					c.new = append(c.new, v.Interface())
+				} else {
+					c.new = append(c.new, nil)
+				}
+			}
+		}
+		changes = append(changes, c)
+	}
+	return changes
+}
+
+func TestChangesetIter(t *testing.T) {
+	db := open(t)
+	defer checkClose(db, t)
+	checkNoError(t, db.FastExec(sessionSchema), "%s")
+
+	changeset := recordChangeset(t, db, `INSERT INTO test VALUES (3, 'c');
+		UPDATE test SET name = 'B' WHERE id = 2;
+		DELETE FROM test WHERE id = 1;`)
+
+	iter, err := NewChangesetIter(changeset)
+	checkNoError(t, err, "couldn't start changeset iteration: %s")
+	changes := readChanges(t, iter)
+	checkNoError(t, iter.Close(), "couldn't close iterator: %s")
+	checkNoError(t, iter.Close(), "couldn't close iterator twice: %s")
+
+	assert.Equal(t, 3, len(changes))
+	byAction := make(map[Action]change)
+	for _, c := range changes {
+		assert.Equal(t, "test", c.table)
+		byAction[c.action] = c
+	}
+	assert.Equal(t, []interface{}{int64(3), "c"}, byAction[Insert].new)
+	// unchanged columns are undefined (nil) except for the primary key
+	assert.Equal(t, []interface{}{int64(2), "b"}, byAction[Update].old)
+	assert.Equal(t, []interface{}{nil, "B"}, byAction[Update].new)
+	assert.Equal(t, []interface{}{int64(1), "a"}, byAction[Delete].old)
+
+	iter, err = NewChangesetIter([]byte("junk"))
+	checkNoError(t, err, "couldn't start changeset iteration: %s")
+	defer iter.Close()
+	_, err = iter.Next()
+	assert.T(t, err != nil, "expected error with invalid changeset")
+}
+
+func TestChangesetStream(t *testing.T) {
+	skipIfCgoCheckActive(t)
+
+	db := open(t)
+	defer checkClose(db, t)
+	checkNoError(t, db.FastExec(sessionSchema), "%s")
+
+	session, err := db.CreateSession("main")
+	checkNoError(t, err, "couldn't create session: %s")
+	defer session.Delete()
+	checkNoError(t, session.Attach("test"), "couldn't attach session: %s")
+	checkNoError(t, db.FastExec("UPDATE test SET name = 'B' WHERE id = 2"), "%s")
+
+	var changeset, patchset bytes.Buffer
+	checkNoError(t, session.ChangesetStream(&changeset), "couldn't stream changeset: %s")
+	checkNoError(t, session.PatchsetStream(&patchset), "couldn't stream patchset: %s")
+	expected, err := session.Changeset()
+	checkNoError(t, err, "couldn't get changeset: %s")
+	assert.Equal(t, expected, changeset.Bytes())
+	assert.T(t, patchset.Len() > 0)
+
+	dst := open(t)
+	defer checkClose(dst, t)
+	checkNoError(t, dst.FastExec(sessionSchema), "%s")
+	checkNoError(t, dst.ApplyChangesetStream(&changeset, nil, nil), "couldn't apply changeset: %s")
+	assert.Equal(t, map[int]string{1: "a", 2: "B"}, selectTestNames(t, dst))
+	err = dst.ApplyChangesetStream(iotest.TimeoutReader(bytes.NewReader(expected)), nil, nil)
+	assert.Equal(t, iotest.ErrTimeout, err)
+
+	var inverse bytes.Buffer
+	checkNoError(t, InvertChangesetStream(bytes.NewReader(expected), &inverse), "couldn't invert changeset: %s")
+	iter, err := NewChangesetIterStream(&inverse)
+	checkNoError(t, err, "couldn't start changeset iteration: %s")
+	changes := readChanges(t, iter)
+	checkNoError(t, iter.Close(), "couldn't close iterator: %s")
+	assert.Equal(t, []change{{table: "test", action: Update,
+		old: []interface{}{int64(2), "B"}, new: []interface{}{nil, "b"}}}, changes)
+
+	var concat bytes.Buffer
+	checkNoError(t, ConcatChangesetsStream(bytes.NewReader(expected), bytes.NewReader(expected), &concat), "couldn't concat changesets: %s")
+	assert.T(t, concat.Len() > 0)
+}
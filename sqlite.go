@@ -188,11 +188,16 @@ type Conn struct {
 	profile         *sqliteProfile
 	progressHandler *sqliteProgressHandler
 	trace           *sqliteTrace
+	traceV2         *sqliteTraceV2
 	commitHook      *sqliteCommitHook
 	rollbackHook    *sqliteRollbackHook
 	updateHook      *sqliteUpdateHook
+	preUpdateHook   *sqlitePreUpdateHook
 	udfs            map[string]*sqliteFunction
 	modules         map[string]*sqliteModule
+	collations      map[string]*sqliteCollation
+	collationNeeded *sqliteCollationNeeded
+	converters      *Converters
 	timeUsed        time.Time
 	nTransaction    uint8
 	// DefaultTimeLayout specifies the layout used to persist time ("2006-01-02 15:04:05.000Z07:00" by default).
@@ -339,9 +339,18 @@ var NullIfEmptyString = true
 var NullIfZeroTime = true
 
 // BindByIndex binds value to the specified host parameter of the prepared statement.
-// Value's type/kind is used to find the storage class.
+// Value's type/kind is used to find the storage class
+// (unsupported types are first looked up in the connection Converters).
 // The leftmost SQL parameter has an index of 1.
 func (s *Stmt) BindByIndex(index int, value interface{}) error {
+	if !isNative(value) {
+		// converters take precedence over driver.Valuer and reflection
+		v, _, err := s.c.Converters().convert(value)
+		if err != nil {
+			return err
+		}
+		value = v
+	}
 	i := C.int(index)
 	var rv C.int
 	switch value := value.(type) {
@@ -398,6 +407,8 @@ func (s *Stmt) BindByIndex(index int, value interface{}) error {
 		}
 	case ZeroBlobLength:
 		rv = C.sqlite3_bind_zeroblob(s.stmt, i, C.int(value))
+	case Pointer:
+		return s.BindPointer(index, value.Type, value.Value)
 	case driver.Valuer:
 		v, err := value.Value()
 		if err != nil {
@@ -612,6 +623,18 @@ func (s *Stmt) SQL() string {
 	return s.sql
 }
 
+// ExpandedSQL returns the SQL text of the prepared statement with bound parameters expanded.
+// Returns an empty string when the expanded text cannot be computed.
+// (See http://sqlite.org/c3ref/expanded_sql.html)
+func (s *Stmt) ExpandedSQL() string {
+	sql := C.sqlite3_expanded_sql(s.stmt)
+	if sql == nil {
+		return ""
+	}
+	defer C.sqlite3_free(unsafe.Pointer(sql))
+	return C.GoString(sql)
+}
+
 // Empty returns true when then input text contains no SQL (if the input is an empty string or a comment)
 func (s *Stmt) Empty() bool {
 	return s.stmt == nil
@@ -667,6 +690,7 @@ func (s *Stmt) ScanByName(name string, value interface{}) (isNull bool, err erro
 //    *time.Time
 //    sql.Scanner
 //    *interface{}
+//    pointer to a type registered in the connection Converters
 //
 // Returns true when column is null.
 // Calls sqlite3_column_(blob|double|int|int64|text) depending on arg type/kind.
@@ -770,6 +794,12 @@ func (s *Stmt) ScanByIndex(index int, value interface{}) (isNull bool, err error
 	case *interface{}:
 		*value, isNull = s.ScanValue(index, false)
 	default:
+		if f, ok := s.c.Converters().scanConverter(value); ok {
+			var v interface{}
+			v, isNull = s.ScanValue(index, false)
+			err = f(v, value)
+			return
+		}
 		return s.ScanReflect(index, value)
 	}
 	return
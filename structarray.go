@@ -0,0 +1,450 @@
+// Copyright 2010 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sqlite
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// StructArray is the Go-language interface definition for a virtual table
+// exposing a slice of structs (or of pointers to structs) to SQLite.
+//
+// Each exported field of the struct is mapped to a column.
+// By default, the column name is the field name but it can be overridden
+// with a "sqlite" tag. A field tagged with "-" is ignored:
+//
+//	type Person struct {
+//		Name     string
+//		Age      int    `sqlite:"age"`
+//		Password string `sqlite:"-"`
+//	}
+//
+// Supported field types are bool, integers, floats, string, []byte, time.Time
+// and pointers to them (a nil pointer is mapped to NULL).
+// Unsigned values greater than math.MaxInt64 are mapped to REAL, as SQLite
+// does for integer literals that do not fit in 64 bits.
+// The rowid of a row is the index of the element in the slice.
+//
+// USAGE:
+//
+//	var persons StructArray
+//	persons, err = db.CreateStructArray("persons", []Person(nil))
+//
+// Each call to CreateStructArray() generates a new virtual table
+// module and a singleton of that virtual table module in the TEMP
+// database.  Both the module and the virtual table instance use the
+// name given by the first parameter.  The virtual table can then be
+// used in prepared statements:
+//
+//	SELECT t.* FROM t, persons p WHERE t.name = p.Name AND p.age > 18;
+//
+// A new slice can be bound as follows:
+//
+//	err = persons.Bind([]Person{{"Bob", 42, ""}, {"Alice", 25, ""}})
+//
+// Equality constraints are pushed down to the Go side: an index is
+// lazily built for each constrained column and then used to look up
+// matching rows instead of scanning the whole slice.
+//
+// A single StructArray object can be rebound multiple times.  But do not
+// attempt to change the bindings of a StructArray while it is in the middle
+// of a query.
+// The application must not change the slice content while a StructArray is in
+// the middle of a query.
+//
+// Cannot be used with Go >= 1.6 and cgocheck enabled.
+type StructArray interface {
+	Bind(slice interface{}) error
+	Drop() error
+}
+
+type structArrayColumn struct {
+	name     string
+	declType string
+	index    int // field index
+}
+
+type structArray struct {
+	c         *Conn
+	name      string
+	sliceType reflect.Type
+	columns   []structArrayColumn
+	content   reflect.Value
+	indexes   map[int]map[interface{}][]int // column index => key => rows
+}
+
+type structArrayModule struct {
+	vTab *structArray
+}
+
+type structArrayCursor struct {
+	vTab    *structArray
+	content reflect.Value
+	rows    []int // matching rows when an index is used
+	scan    bool  // full scan
+	i       int
+}
+
+// blobKey distinguishes blobs from texts in indexes.
+type blobKey string
+
+// CreateStructArray create a specific instance of a struct array object.
+// slice must be a slice of structs or of pointers to structs and is initially bound.
+// It can be a nil slice: only its type is used to declare the columns.
+//
+// Each struct array object corresponds to a virtual table in the TEMP database
+// with the specified name.
+//
+// Destroy the struct array object by dropping the virtual table.  If not done
+// explicitly by the application, the virtual table will be dropped implicitly
+// by the system when the database connection is closed.
+func (c *Conn) CreateStructArray(name string, slice interface{}) (StructArray, error) {
+	t := reflect.TypeOf(slice)
+	if t == nil || t.Kind() != reflect.Slice {
+		return nil, fmt.Errorf("expected a slice of structs but got %v", t)
+	}
+	columns, err := structArrayColumns(t.Elem())
+	if err != nil {
+		return nil, err
+	}
+	m := &structArray{c: c, name: name, sliceType: t, columns: columns, content: reflect.ValueOf(slice)}
+	if err = c.CreateModule(name, structArrayModule{m}); err != nil {
+		return nil, err
+	}
+	if err = c.FastExec(fmt.Sprintf(`CREATE VIRTUAL TABLE temp."%s" USING "%s"`, escapeQuote(name), escapeQuote(name))); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func structArrayColumns(t reflect.Type) ([]structArrayColumn, error) {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("expected a slice of structs but got a slice of %v", t)
+	}
+	var columns []structArrayColumn
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.PkgPath != "" || f.Anonymous { // unexported or embedded
+			continue
+		}
+		name := f.Name
+		if tag := f.Tag.Get("sqlite"); tag == "-" {
+			continue
+		} else if tag != "" {
+			name = tag
+		}
+		declType := structArrayDeclType(f.Type)
+		if declType == "" {
+			return nil, fmt.Errorf("unsupported type %v for field %s", f.Type, f.Name)
+		}
+		columns = append(columns, structArrayColumn{name: name, declType: declType, index: i})
+	}
+	if len(columns) == 0 {
+		return nil, fmt.Errorf("no column found in %v", t)
+	}
+	return columns, nil
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+func structArrayDeclType(t reflect.Type) string {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == timeType {
+		return "TIMESTAMP"
+	}
+	switch t.Kind() {
+	case reflect.Bool, reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "INTEGER"
+	case reflect.Float32, reflect.Float64:
+		return "REAL"
+	case reflect.String:
+		return "TEXT"
+	case reflect.Slice:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return "BLOB"
+		}
+	}
+	return ""
+}
+
+// Bind a new slice to a specific struct array object.
+// The slice must have the same type as the one used to create the struct array.
+//
+// The slice bound must be unchanged for the duration of
+// any query against the corresponding virtual table.
+func (m *structArray) Bind(slice interface{}) error {
+	if m.c == nil {
+		return errors.New("sqlite struct array has been dropped")
+	}
+	if t := reflect.TypeOf(slice); t != m.sliceType {
+		return fmt.Errorf("expected %v but got %v", m.sliceType, t)
+	}
+	m.content = reflect.ValueOf(slice)
+	m.indexes = nil
+	return nil
+}
+
+// Drop underlying virtual table.
+func (m *structArray) Drop() error {
+	if m == nil {
+		return errors.New("nil sqlite struct array")
+	}
+	if m.c == nil {
+		return nil
+	}
+	err := m.c.FastExec(fmt.Sprintf(`DROP TABLE temp."%s"`, escapeQuote(m.name)))
+	if err != nil {
+		return err
+	}
+	m.c = nil
+	return nil
+}
+
+func (vm structArrayModule) Create(c *Conn, args []string) (VTab, error) {
+	return vm.Connect(c, args)
+}
+func (vm structArrayModule) Connect(c *Conn, args []string) (VTab, error) {
+	m := vm.vTab
+	cols := make([]string, len(m.columns))
+	for i, col := range m.columns {
+		cols[i] = fmt.Sprintf(`"%s" %s`, escapeQuote(col.name), col.declType)
+	}
+	if err := c.DeclareVTab(fmt.Sprintf("CREATE TABLE x(%s)", strings.Join(cols, ", "))); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+func (vm structArrayModule) DestroyModule() {
+}
+
+// BestIndex uses the first usable equality constraint on the rowid or on a column.
+// IdxNum is 0 for a full scan, 1 for a rowid lookup and column index + 2 for a column lookup.
+// Constraints are not omitted so that SQLite double checks them.
+func (m *structArray) BestIndex(info *IndexInfo) error {
+	n := float64(m.content.Len())
+	info.EstimatedCost = n
+	info.EstimatedRows = int64(n)
+	for i, c := range info.Constraints {
+		if c.Usable && c.Op == OpEq && c.Column < 0 {
+			info.ConstraintUsages[i].ArgvIndex = 1
+			info.IdxNum = 1
+			info.EstimatedCost = 1
+			info.EstimatedRows = 1
+			info.IdxFlags |= IndexScanUnique
+			return nil
+		}
+	}
+	for i, c := range info.Constraints {
+		if !c.Usable || c.Op != OpEq || c.Column < 0 {
+			continue
+		}
+		if m.columns[c.Column].declType == "TEXT" && c.Collation != "" && !strings.EqualFold(c.Collation, "BINARY") {
+			continue
+		}
+		info.ConstraintUsages[i].ArgvIndex = 1
+		info.IdxNum = c.Column + 2
+		info.EstimatedCost = math.Log2(n + 1)
+		info.EstimatedRows = 10
+		break
+	}
+	return nil
+}
+func (m *structArray) Disconnect() error {
+	return nil
+}
+func (m *structArray) Destroy() error {
+	return nil
+}
+func (m *structArray) Open() (VTabCursor, error) {
+	return &structArrayCursor{vTab: m}, nil
+}
+
+// index returns the index of the specified column, building it if needed.
+func (m *structArray) index(col int) map[interface{}][]int {
+	if idx, ok := m.indexes[col]; ok {
+		return idx
+	}
+	idx := make(map[interface{}][]int)
+	for row := 0; row < m.content.Len(); row++ {
+		if key, ok := structArrayKey(m.value(m.content, row, col)); ok {
+			idx[key] = append(idx[key], row)
+		}
+	}
+	if m.indexes == nil {
+		m.indexes = make(map[int]map[interface{}][]int)
+	}
+	m.indexes[col] = idx
+	return idx
+}
+
+// value returns the SQL value of the specified row and column: nil, int64, float64, string or []byte.
+func (m *structArray) value(content reflect.Value, row, col int) interface{} {
+	e := content.Index(row)
+	if e.Kind() == reflect.Ptr {
+		if e.IsNil() {
+			return nil
+		}
+		e = e.Elem()
+	}
+	f := e.Field(m.columns[col].index)
+	if f.Kind() == reflect.Ptr {
+		if f.IsNil() {
+			return nil
+		}
+		f = f.Elem()
+	}
+	if f.Type() == timeType {
+		t := f.Interface().(time.Time)
+		if NullIfZeroTime && t.IsZero() {
+			return nil
+		} else if m.c == nil || m.c.DefaultTimeLayout == "" {
+			return t.Unix()
+		}
+		return t.Format(m.c.DefaultTimeLayout)
+	}
+	switch f.Kind() {
+	case reflect.Bool:
+		if f.Bool() {
+			return int64(1)
+		}
+		return int64(0)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return f.Int()
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		u := f.Uint()
+		if u > math.MaxInt64 { // like SQLite does with integer literals too large for int64
+			return float64(u)
+		}
+		return int64(u)
+	case reflect.Float32, reflect.Float64:
+		return f.Float()
+	case reflect.String:
+		return f.String()
+	case reflect.Slice:
+		return f.Bytes()
+	}
+	return nil
+}
+
+// structArrayKey normalizes an SQL value to be used as an index key.
+func structArrayKey(v interface{}) (interface{}, bool) {
+	switch v := v.(type) {
+	case int64:
+		return v, true
+	case float64:
+		if v >= math.MinInt64 && v < math.MaxInt64 {
+			if i := int64(v); float64(i) == v {
+				return i, true
+			}
+		}
+		return v, true
+	case string:
+		return v, true
+	case []byte:
+		return blobKey(v), true
+	}
+	return nil, false
+}
+
+// argKey normalizes a constraint argument according to the column affinity.
+// Returns false when the argument cannot be converted safely.
+func (m *structArray) argKey(col int, arg interface{}) (interface{}, bool) {
+	switch m.columns[col].declType {
+	case "INTEGER", "REAL", "TIMESTAMP":
+		if s, ok := arg.(string); ok {
+			s = strings.TrimSpace(s)
+			if i, err := strconv.ParseInt(s, 10, 64); err == nil {
+				return i, true
+			} else if f, err := strconv.ParseFloat(s, 64); err == nil {
+				return structArrayKey(f)
+			}
+			if m.columns[col].declType != "TIMESTAMP" { // text never equals a number
+				return structArrayKey(arg)
+			}
+			return nil, false
+		}
+	case "TEXT":
+		switch arg := arg.(type) {
+		case int64:
+			return strconv.FormatInt(arg, 10), true
+		case float64:
+			return nil, false
+		}
+	}
+	return structArrayKey(arg)
+}
+
+func (vc *structArrayCursor) Close() error {
+	return nil
+}
+func (vc *structArrayCursor) Filter(idxNum int, idxStr string, args []interface{}) error {
+	m := vc.vTab
+	vc.content = m.content
+	vc.i = 0
+	vc.rows = nil
+	vc.scan = false
+	switch {
+	case idxNum == 1:
+		if rowid, ok := args[0].(int64); ok && rowid >= 0 && rowid < int64(vc.content.Len()) {
+			vc.rows = []int{int(rowid)}
+		}
+	case idxNum > 1:
+		col := idxNum - 2
+		if key, ok := m.argKey(col, args[0]); ok {
+			vc.rows = m.index(col)[key]
+		} else if args[0] != nil {
+			vc.scan = true
+		}
+	default:
+		vc.scan = true
+	}
+	return nil
+}
+func (vc *structArrayCursor) Next() error {
+	vc.i++
+	return nil
+}
+func (vc *structArrayCursor) EOF() bool {
+	if vc.scan {
+		return vc.i >= vc.content.Len()
+	}
+	return vc.i >= len(vc.rows)
+}
+func (vc *structArrayCursor) row() int {
+	if vc.scan {
+		return vc.i
+	}
+	return vc.rows[vc.i]
+}
+func (vc *structArrayCursor) Column(c *Context, col int) error {
+	switch v := vc.vTab.value(vc.content, vc.row(), col).(type) {
+	case nil:
+		c.ResultNull()
+	case int64:
+		c.ResultInt64(v)
+	case float64:
+		c.ResultDouble(v)
+	case string:
+		c.ResultText(v)
+	case []byte:
+		c.ResultBlob(v)
+	}
+	return nil
+}
+func (vc *structArrayCursor) Rowid() (int64, error) {
+	return int64(vc.row()), nil
+}
@@ -0,0 +1,164 @@
+// Copyright 2010 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sqlite_test
+
+import (
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/bmizerany/assert"
+	. "github.com/gwenn/gosqlite"
+)
+
+type person struct {
+	Name     string
+	Age      int     `sqlite:"age"`
+	Nickname *string `sqlite:"nick"`
+	Secret   string  `sqlite:"-"`
+	hidden   bool
+}
+
+func TestStructArrayModule(t *testing.T) {
+	skipIfCgoCheckActive(t)
+	db := open(t)
+	defer checkClose(db, t)
+
+	err := db.FastExec(`CREATE TABLE job (name TEXT, title TEXT);
+		INSERT INTO job VALUES ('Bob', 'dev'), ('Alice', 'ops'), ('Carol', 'qa');`)
+	checkNoError(t, err, "%s")
+
+	bobby := "Bobby"
+	persons, err := db.CreateStructArray("persons", []person{{Name: "Bob", Age: 42, Nickname: &bobby}, {Name: "Alice", Age: 25}})
+	checkNoError(t, err, "error creating struct array: %s")
+
+	var count int
+	err = db.OneValue("SELECT count(*) FROM persons", &count)
+	checkNoError(t, err, "%s")
+	assert.Equal(t, 2, count)
+
+	var nick string
+	err = db.OneValue("SELECT nick FROM persons WHERE Name = 'Bob'", &nick)
+	checkNoError(t, err, "%s")
+	assert.Equal(t, "Bobby", nick)
+
+	// equality constraint with affinity conversion
+	err = db.OneValue("SELECT Name FROM persons WHERE age = ?", &nick, "25")
+	checkNoError(t, err, "%s")
+	assert.Equal(t, "Alice", nick)
+	err = db.OneValue("SELECT count(*) FROM persons WHERE nick IS NULL", &count)
+	checkNoError(t, err, "%s")
+	assert.Equal(t, 1, count)
+	err = db.OneValue("SELECT count(*) FROM persons WHERE Name = 'bob' COLLATE NOCASE", &count)
+	checkNoError(t, err, "%s")
+	assert.Equal(t, 1, count)
+	err = db.OneValue("SELECT Name FROM persons WHERE rowid = 1", &nick)
+	checkNoError(t, err, "%s")
+	assert.Equal(t, "Alice", nick)
+
+	// constraints pushed down to BestIndex (idxNum: 1 = rowid, 2 + column = index lookup)
+	assert.Equal(t, "INDEX 1:", structArrayPlan(t, db, "SELECT Name FROM persons WHERE rowid = 1"))
+	assert.Equal(t, "INDEX 2:", structArrayPlan(t, db, "SELECT age FROM persons WHERE Name = 'Bob'"))
+	assert.Equal(t, "INDEX 3:", structArrayPlan(t, db, "SELECT Name FROM persons WHERE age = 25"))
+	assert.Equal(t, "INDEX 0:", structArrayPlan(t, db, "SELECT Name FROM persons WHERE age > 25"))
+
+	s, err := db.Prepare("SELECT p.Name, j.title FROM job j, persons p WHERE j.name = p.Name ORDER BY p.age")
+	checkNoError(t, err, "%s")
+	defer checkFinalize(s, t)
+	var name, title string
+	var names []string
+	err = s.Select(func(s *Stmt) error {
+		if err := s.Scan(&name, &title); err != nil {
+			return err
+		}
+		names = append(names, name+":"+title)
+		return nil
+	})
+	checkNoError(t, err, "%s")
+	assert.Equal(t, []string{"Alice:ops", "Bob:dev"}, names)
+
+	err = persons.Bind([]person{{Name: "Carol", Age: 33}})
+	checkNoError(t, err, "error binding struct array: %s")
+	names = nil
+	err = s.Select(func(s *Stmt) error {
+		if err := s.Scan(&name, &title); err != nil {
+			return err
+		}
+		names = append(names, name+":"+title)
+		return nil
+	})
+	checkNoError(t, err, "%s")
+	assert.Equal(t, []string{"Carol:qa"}, names)
+
+	err = persons.Bind([]int64{1})
+	assert.T(t, err != nil, "expected type mismatch error")
+
+	checkNoError(t, persons.Drop(), "%s")
+}
+
+func TestStructArrayUint64(t *testing.T) {
+	skipIfCgoCheckActive(t)
+	db := open(t)
+	defer checkClose(db, t)
+
+	type counter struct {
+		N uint64
+	}
+	counters, err := db.CreateStructArray("counters", []counter{{N: 1}, {N: math.MaxUint64}, {N: 1 << 63}})
+	checkNoError(t, err, "error creating struct array: %s")
+
+	var count int
+	err = db.OneValue("SELECT count(*) FROM counters WHERE N = -1", &count)
+	checkNoError(t, err, "%s")
+	assert.Equal(t, 0, count, "uint64 must not wrap to a negative integer")
+	err = db.OneValue("SELECT count(*) FROM counters WHERE N = ?", &count, int64(math.MinInt64))
+	checkNoError(t, err, "%s")
+	assert.Equal(t, 0, count, "uint64 must not wrap to a negative integer")
+	err = db.OneValue("SELECT count(*) FROM counters WHERE N = ?", &count, int64(math.MaxInt64))
+	checkNoError(t, err, "%s")
+	assert.Equal(t, 0, count)
+	err = db.OneValue("SELECT count(*) FROM counters WHERE N = 18446744073709551615", &count)
+	checkNoError(t, err, "%s")
+	assert.Equal(t, 1, count)
+	err = db.OneValue("SELECT count(*) FROM counters WHERE N = 1", &count)
+	checkNoError(t, err, "%s")
+	assert.Equal(t, 1, count)
+
+	var typ string
+	err = db.OneValue("SELECT typeof(N) FROM counters WHERE rowid = 1", &typ)
+	checkNoError(t, err, "%s")
+	assert.Equal(t, "real", typ)
+
+	checkNoError(t, counters.Drop(), "%s")
+}
+
+// structArrayPlan returns the "INDEX <idxNum>:" part of the query plan of a struct array scan.
+func structArrayPlan(t *testing.T, db *Conn, sql string) string {
+	s, err := db.Prepare("EXPLAIN QUERY PLAN " + sql)
+	checkNoError(t, err, "%s")
+	defer checkFinalize(s, t)
+	var detail string
+	err = s.Select(func(s *Stmt) error {
+		return s.Scan(nil, nil, nil, &detail)
+	})
+	checkNoError(t, err, "%s")
+	if i := strings.Index(detail, "INDEX "); i >= 0 {
+		detail = detail[i:]
+		if j := strings.Index(detail, ":"); j >= 0 {
+			return detail[:j+1]
+		}
+	}
+	return detail
+}
+
+func TestStructArrayUnsupported(t *testing.T) {
+	db := open(t)
+	defer checkClose(db, t)
+
+	_, err := db.CreateStructArray("ints", []int64{})
+	assert.T(t, err != nil, "expected error with a slice of non-struct")
+	_, err = db.CreateStructArray("chans", []struct{ C chan int }{})
+	assert.T(t, err != nil, "expected error with an unsupported field type")
+}
@@ -18,6 +18,12 @@ void goSqlite3Profile(sqlite3 *db, void *udp) {
 	sqlite3_profile(db, goXProfile, udp);
 }
 
+extern int goXTraceV2(unsigned int t, void *udp, void *p, void *x);
+
+int goSqlite3TraceV2(sqlite3 *db, unsigned int mask, void *udp) {
+	return sqlite3_trace_v2(db, mask, goXTraceV2, udp);
+}
+
 extern int goXAuth(void *udp, int action, const char *arg1, const char *arg2, const char *dbName, const char *triggerName);
 
 int goSqlite3SetAuthorizer(sqlite3 *db, void *udp) {
@@ -10,6 +10,7 @@ package sqlite
 
 void goSqlite3Trace(sqlite3 *db, void *udp);
 void goSqlite3Profile(sqlite3 *db, void *udp);
+int goSqlite3TraceV2(sqlite3 *db, unsigned int mask, void *udp);
 int goSqlite3SetAuthorizer(sqlite3 *db, void *udp);
 int goSqlite3BusyHandler(sqlite3 *db, void *udp);
 void goSqlite3ProgressHandler(sqlite3 *db, int numOps, void *udp);
@@ -26,6 +27,7 @@ import "C"
 import (
 	"fmt"
 	"io"
+	"strings"
 	"time"
 	"unsafe"
 )
@@ -49,10 +51,13 @@ func goXTrace(udp unsafe.Pointer, sql *C.char) {
 // Prepared statement placeholders are replaced/logged with their assigned values.
 // There can only be a single tracer defined for each database connection.
 // Setting a new tracer clears the old one.
+// It also clears the profiler and the tracer registered with Conn.Profile and Conn.TraceV2.
 // If f is nil, the current tracer is removed.
 // Cannot be used with Go >= 1.6 and cgocheck enabled.
 // (See sqlite3_trace, http://sqlite.org/c3ref/profile.html)
 func (c *Conn) Trace(f Tracer, udp interface{}) {
+	c.profile = nil
+	c.traceV2 = nil
 	if f == nil {
 		c.trace = nil
 		C.sqlite3_trace(c.db, nil, nil)
@@ -82,10 +87,12 @@ func goXProfile(udp unsafe.Pointer, sql *C.char, nanoseconds C.sqlite3_uint64) {
 // Prepared statement placeholders are not logged with their assigned values.
 // There can only be a single profiler defined for each database connection.
 // Setting a new profiler clears the old one.
+// It also clears the tracer registered with Conn.Trace.
 // If f is nil, the current profiler is removed.
 // Cannot be used with Go >= 1.6 and cgocheck enabled.
 // (See sqlite3_profile, http://sqlite.org/c3ref/profile.html)
 func (c *Conn) Profile(f Profiler, udp interface{}) {
+	c.trace = nil
 	if f == nil {
 		c.profile = nil
 		C.sqlite3_profile(c.db, nil, nil)
@@ -96,6 +103,94 @@ func (c *Conn) Profile(f Profiler, udp interface{}) {
 	C.goSqlite3Profile(c.db, unsafe.Pointer(c.profile))
 }
 
+// TraceEvent enumerates trace event codes.
+// They can be combined to build the mask of Conn.TraceV2.
+type TraceEvent uint32
+
+// Trace event codes
+const (
+	TraceStmt    TraceEvent = C.SQLITE_TRACE_STMT
+	TraceProfile TraceEvent = C.SQLITE_TRACE_PROFILE
+	TraceRow     TraceEvent = C.SQLITE_TRACE_ROW
+	TraceClose   TraceEvent = C.SQLITE_TRACE_CLOSE
+)
+
+func (e TraceEvent) String() string {
+	switch e {
+	case TraceStmt:
+		return "Stmt"
+	case TraceProfile:
+		return "Profile"
+	case TraceRow:
+		return "Row"
+	case TraceClose:
+		return "Close"
+	}
+	return fmt.Sprintf("Unknown TraceEvent: %d", e)
+}
+
+// TraceInfo describes a trace event.
+type TraceInfo struct {
+	Event TraceEvent
+	// SQL text of the statement being run (empty for TraceClose)
+	SQL string
+	// Expanded SQL (with bound parameter values) for TraceStmt
+	// or the trigger comment when a trigger program starts.
+	ExpandedSQL string
+	// Estimated statement run time for TraceProfile
+	Duration time.Duration
+}
+
+// TraceV2Handler is the signature of a trace function.
+// See Conn.TraceV2
+type TraceV2Handler func(udp interface{}, info TraceInfo)
+
+type sqliteTraceV2 struct {
+	f   TraceV2Handler
+	udp interface{}
+}
+
+//export goXTraceV2
+func goXTraceV2(t C.uint, udp, p, x unsafe.Pointer) C.int {
+	arg := (*sqliteTraceV2)(udp)
+	info := TraceInfo{Event: TraceEvent(t)}
+	if info.Event != TraceClose {
+		info.SQL = C.GoString(C.sqlite3_sql((*C.sqlite3_stmt)(p)))
+	}
+	switch info.Event {
+	case TraceStmt:
+		info.ExpandedSQL = C.GoString((*C.char)(x))
+		if !strings.HasPrefix(info.ExpandedSQL, "--") {
+			if sql := C.sqlite3_expanded_sql((*C.sqlite3_stmt)(p)); sql != nil {
+				info.ExpandedSQL = C.GoString(sql)
+				C.sqlite3_free(unsafe.Pointer(sql))
+			}
+		}
+	case TraceProfile:
+		info.Duration = time.Duration(int64(*(*C.sqlite3_int64)(x)))
+	}
+	arg.f(arg.udp, info)
+	return 0
+}
+
+// TraceV2 registers or clears a trace function for the events specified by mask
+// (TraceStmt|TraceProfile|TraceRow|TraceClose).
+// It replaces the tracer and profiler registered with Conn.Trace and Conn.Profile.
+// If f is nil or mask is zero, the current tracer is removed.
+// Cannot be used with Go >= 1.6 and cgocheck enabled.
+// (See http://sqlite.org/c3ref/trace_v2.html)
+func (c *Conn) TraceV2(mask TraceEvent, f TraceV2Handler, udp interface{}) error {
+	c.trace = nil
+	c.profile = nil
+	if f == nil || mask == 0 {
+		c.traceV2 = nil
+		return c.error(C.sqlite3_trace_v2(c.db, 0, nil, nil), "<Conn.TraceV2")
+	}
+	// To make sure it is not gced, keep a reference in the connection.
+	c.traceV2 = &sqliteTraceV2{f, udp}
+	return c.error(C.goSqlite3TraceV2(c.db, C.uint(mask), unsafe.Pointer(c.traceV2)), "Conn.TraceV2")
+}
+
 // Auth enumerates Authorizer return codes
 type Auth int32
 
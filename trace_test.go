@@ -99,6 +99,77 @@ func TestProfile(t *testing.T) {
 	createTable(db, t)
 }
 
+func TestTraceV2(t *testing.T) {
+	skipIfCgoCheckActive(t)
+
+	db, err := Open(":memory:", OpenReadWrite, OpenCreate, OpenFullMutex)
+	checkNoError(t, err, "couldn't open database file: %s")
+	var events []TraceEvent
+	var sqls []string
+	var rowSQL, profileSQL string
+	err = db.TraceV2(TraceStmt|TraceProfile|TraceRow|TraceClose, func(udp interface{}, info TraceInfo) {
+		assert.Equal(t, "udp", udp)
+		events = append(events, info.Event)
+		switch info.Event {
+		case TraceStmt:
+			sqls = append(sqls, info.ExpandedSQL)
+		case TraceProfile:
+			assert.T(t, info.Duration >= 0, "duration")
+			profileSQL = info.SQL
+		case TraceRow:
+			rowSQL = info.SQL
+		case TraceClose:
+			assert.Equal(t, "", info.SQL, "no stmt on close")
+		}
+	}, "udp")
+	checkNoError(t, err, "couldn't set tracer: %s")
+
+	var i int
+	err = db.OneValue("SELECT ? + 1", &i, 41)
+	checkNoError(t, err, "couldn't select: %s")
+	assert.Equal(t, 42, i)
+	assert.Equal(t, []string{"SELECT 41 + 1"}, sqls)
+	assert.Equal(t, "SELECT ? + 1", rowSQL)
+	assert.Equal(t, "SELECT ? + 1", profileSQL)
+	assert.Equal(t, []TraceEvent{TraceStmt, TraceRow, TraceProfile}, events)
+
+	checkNoError(t, db.TraceV2(TraceClose, func(udp interface{}, info TraceInfo) {
+		events = append(events, info.Event)
+	}, nil), "couldn't set tracer: %s")
+	events = nil
+	checkNoError(t, db.FastExec("SELECT 1"), "%s")
+	checkNoError(t, db.Close(), "couldn't close database: %s")
+	assert.Equal(t, []TraceEvent{TraceClose}, events)
+
+	db = open(t)
+	defer checkClose(db, t)
+	checkNoError(t, db.TraceV2(0, nil, nil), "couldn't clear tracer: %s")
+}
+
+func TestTraceReplacesTraceV2(t *testing.T) {
+	skipIfCgoCheckActive(t)
+
+	db := open(t)
+	defer checkClose(db, t)
+	var calls []string
+	checkNoError(t, db.TraceV2(TraceStmt, func(udp interface{}, info TraceInfo) {
+		calls = append(calls, "v2")
+	}, nil), "couldn't set tracer: %s")
+	db.Trace(func(udp interface{}, sql string) {
+		calls = append(calls, "trace")
+	}, nil)
+	checkNoError(t, db.FastExec("SELECT 1"), "%s")
+	assert.Equal(t, []string{"trace"}, calls)
+
+	calls = nil
+	db.Profile(func(udp interface{}, sql string, d time.Duration) {
+		calls = append(calls, "profile")
+	}, nil)
+	checkNoError(t, db.FastExec("SELECT 1"), "%s")
+	assert.Equal(t, []string{"profile"}, calls)
+	db.Profile(nil, nil)
+}
+
 func TestLog(t *testing.T) {
 	Log(0, "One message")
 }
@@ -0,0 +1,160 @@
+// Copyright 2010 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sqlite
+
+/*
+#include <sqlite3.h>
+*/
+import "C"
+
+import (
+	"unsafe"
+)
+
+// Value is an SQL value passed to a function, a virtual table or a hook.
+// Unless it is obtained with Dup, it is only valid during the callback invocation.
+// It gives access to the value without conversion to an interface{} and without copy.
+// (See http://sqlite.org/c3ref/value.html)
+type Value C.sqlite3_value
+
+// Arg returns the SQL function parameter value.
+// The leftmost value is number 0.
+func (c *FunctionContext) Arg(i int) *Value {
+	return (*Value)((*[1 << 20]*C.sqlite3_value)(unsafe.Pointer(c.argv))[i])
+}
+
+// goRawValues returns argv values as Value pointers.
+func goRawValues(argc int, argv unsafe.Pointer) []*Value {
+	if argc == 0 {
+		return nil
+	}
+	values := make([]*Value, argc)
+	for i, v := range (*[1 << 20]*C.sqlite3_value)(argv)[:argc:argc] {
+		values[i] = (*Value)(v)
+	}
+	return values
+}
+
+// Type returns the value type.
+// (See sqlite3_value_type, http://sqlite.org/c3ref/value_blob.html)
+func (v *Value) Type() Type {
+	return Type(C.sqlite3_value_type((*C.sqlite3_value)(v)))
+}
+
+// NumericType returns the value numeric type (with possible conversion).
+// (See sqlite3_value_numeric_type, http://sqlite.org/c3ref/value_blob.html)
+func (v *Value) NumericType() Type {
+	return Type(C.sqlite3_value_numeric_type((*C.sqlite3_value)(v)))
+}
+
+// Bool returns the value as a boolean.
+func (v *Value) Bool() bool {
+	return v.Int64() != 0
+}
+
+// Int64 returns the value as an integer.
+// (See sqlite3_value_int64, http://sqlite.org/c3ref/value_blob.html)
+func (v *Value) Int64() int64 {
+	return int64(C.sqlite3_value_int64((*C.sqlite3_value)(v)))
+}
+
+// Double returns the value as a float.
+// (See sqlite3_value_double, http://sqlite.org/c3ref/value_blob.html)
+func (v *Value) Double() float64 {
+	return float64(C.sqlite3_value_double((*C.sqlite3_value)(v)))
+}
+
+// Text returns a copy of the value as a string.
+// (See sqlite3_value_text, http://sqlite.org/c3ref/value_blob.html)
+func (v *Value) Text() string {
+	p := C.sqlite3_value_text((*C.sqlite3_value)(v))
+	if p == nil {
+		return ""
+	}
+	n := C.sqlite3_value_bytes((*C.sqlite3_value)(v))
+	return C.GoStringN((*C.char)(unsafe.Pointer(p)), n)
+}
+
+// Bytes returns the value content (blob or text) without copying it.
+// The returned slice must not be modified and must not be used once the value is
+// released (after the callback returns or once a type conversion happens).
+// (See sqlite3_value_blob, http://sqlite.org/c3ref/value_blob.html)
+func (v *Value) Bytes() []byte {
+	p := C.sqlite3_value_blob((*C.sqlite3_value)(v))
+	if p == nil {
+		return nil
+	}
+	n := int(C.sqlite3_value_bytes((*C.sqlite3_value)(v)))
+	return (*[1 << 30]byte)(p)[:n:n]
+}
+
+// Blob returns a copy of the value as a blob.
+// (See sqlite3_value_blob, http://sqlite.org/c3ref/value_blob.html)
+func (v *Value) Blob() []byte {
+	b := v.Bytes()
+	if b == nil {
+		return nil
+	}
+	return append([]byte(nil), b...)
+}
+
+// Interface returns the value converted to a Go value depending on its type
+// (see FunctionContext.Value).
+func (v *Value) Interface() interface{} {
+	switch v.Type() {
+	case Null:
+		if p, ok := v.pointer(); ok {
+			return p
+		}
+		return nil
+	case Text:
+		return v.Text()
+	case Integer:
+		return v.Int64()
+	case Float:
+		return v.Double()
+	case Blob:
+		return v.Blob()
+	}
+	panic("The value type is not one of SQLITE_INTEGER, SQLITE_FLOAT, SQLITE_TEXT, SQLITE_BLOB, or SQLITE_NULL")
+}
+
+// SubType returns the value subtype.
+// (See sqlite3_value_subtype, http://sqlite.org/c3ref/value_subtype.html)
+func (v *Value) SubType() uint {
+	return uint(C.sqlite3_value_subtype((*C.sqlite3_value)(v)))
+}
+
+// NoChange tells, within a virtual table UPDATE, if the column is unchanged.
+// (See sqlite3_value_nochange, http://sqlite.org/c3ref/value_blob.html)
+func (v *Value) NoChange() bool {
+	return C.sqlite3_value_nochange((*C.sqlite3_value)(v)) != 0
+}
+
+// FromBind tells if the value originated from a bound parameter.
+// (See sqlite3_value_frombind, http://sqlite.org/c3ref/value_blob.html)
+func (v *Value) FromBind() bool {
+	return C.sqlite3_value_frombind((*C.sqlite3_value)(v)) != 0
+}
+
+// Dup makes a protected copy of the value which can be used after the callback returns.
+// The copy must be released with Free.
+// (See sqlite3_value_dup, http://sqlite.org/c3ref/value_dup.html)
+func (v *Value) Dup() *Value {
+	return (*Value)(C.sqlite3_value_dup((*C.sqlite3_value)(v)))
+}
+
+// Free releases a value obtained with Dup.
+// (See sqlite3_value_free, http://sqlite.org/c3ref/value_dup.html)
+func (v *Value) Free() {
+	C.sqlite3_value_free((*C.sqlite3_value)(v))
+}
+
+// VTabNoChange tells, within VTabCursor.Column, that the column is unchanged by an UPDATE
+// and that its value does not need to be computed.
+// (See http://sqlite.org/c3ref/vtab_nochange.html)
+func (c *Context) VTabNoChange() bool {
+	return C.sqlite3_vtab_nochange((*C.sqlite3_context)(c)) != 0
+}
@@ -0,0 +1,265 @@
+/*
+** This file implements a read-only VIRTUAL TABLE that contains the
+** content of a C-language array of float, text or blob values.
+** It is modeled on the intarray virtual table (see intarray.c).
+*/
+#include <sqlite3.h>
+#include <string.h>
+
+/*
+** An sqlite3_valuearray is an abstract type to stores an instance of
+** an array of values.
+*/
+typedef struct sqlite3_valuearray sqlite3_valuearray;
+
+/*
+** Definition of the sqlite3_valuearray object.
+**
+** Float values are stored in aDouble.
+** Text and blob values are concatenated in zData: the i-th value
+** starts at aOffset[i] and ends at aOffset[i+1].
+*/
+struct sqlite3_valuearray {
+  int eType;                /* SQLITE_FLOAT, SQLITE_TEXT or SQLITE_BLOB */
+  int n;                    /* Number of elements in the array */
+  double *aDouble;          /* Contents of the float array */
+  const char *zData;        /* Contents of the text/blob array */
+  sqlite3_int64 *aOffset;   /* Offsets of text/blob values in zData */
+};
+
+/* Objects used internally by the virtual table implementation */
+typedef struct valuearray_vtab valuearray_vtab;
+typedef struct valuearray_cursor valuearray_cursor;
+
+/* A valuearray table object */
+struct valuearray_vtab {
+  sqlite3_vtab base;             /* Base class */
+  sqlite3_valuearray *pContent;  /* Content of the array */
+};
+
+/* A valuearray cursor object */
+struct valuearray_cursor {
+  sqlite3_vtab_cursor base;    /* Base class */
+  int i;                       /* Current cursor position */
+};
+
+/*
+** Table destructor for the valuearray module.
+*/
+static int valuearrayDestroy(sqlite3_vtab *p){
+  valuearray_vtab *pVtab = (valuearray_vtab*)p;
+  sqlite3_free(pVtab);
+  return 0;
+}
+
+/*
+** Table constructor for the valuearray module.
+*/
+static int valuearrayCreate(
+  sqlite3 *db,              /* Database where module is created */
+  void *pAux,               /* clientdata for the module */
+  int argc,                 /* Number of arguments */
+  const char *const*argv,   /* Value for all arguments */
+  sqlite3_vtab **ppVtab,    /* Write the new virtual table object here */
+  char **pzErr              /* Put error message text here */
+){
+  int rc = SQLITE_NOMEM;
+  valuearray_vtab *pVtab = sqlite3_malloc(sizeof(valuearray_vtab));
+
+  if( pVtab ){
+    const char *zSql;
+    memset(pVtab, 0, sizeof(valuearray_vtab));
+    pVtab->pContent = (sqlite3_valuearray*)pAux;
+    switch( pVtab->pContent->eType ){
+      case SQLITE_FLOAT: zSql = "CREATE TABLE x(value REAL)"; break;
+      case SQLITE_TEXT:  zSql = "CREATE TABLE x(value TEXT)"; break;
+      default:           zSql = "CREATE TABLE x(value BLOB)"; break;
+    }
+    rc = sqlite3_declare_vtab(db, zSql);
+  }
+  *ppVtab = (sqlite3_vtab *)pVtab;
+  return rc;
+}
+
+/*
+** Open a new cursor on the valuearray table.
+*/
+static int valuearrayOpen(sqlite3_vtab *pVTab, sqlite3_vtab_cursor **ppCursor){
+  int rc = SQLITE_NOMEM;
+  valuearray_cursor *pCur;
+  pCur = sqlite3_malloc(sizeof(valuearray_cursor));
+  if( pCur ){
+    memset(pCur, 0, sizeof(valuearray_cursor));
+    *ppCursor = (sqlite3_vtab_cursor *)pCur;
+    rc = SQLITE_OK;
+  }
+  return rc;
+}
+
+/*
+** Close a valuearray table cursor.
+*/
+static int valuearrayClose(sqlite3_vtab_cursor *cur){
+  valuearray_cursor *pCur = (valuearray_cursor *)cur;
+  sqlite3_free(pCur);
+  return SQLITE_OK;
+}
+
+/*
+** Retrieve a column of data.
+*/
+static int valuearrayColumn(sqlite3_vtab_cursor *cur, sqlite3_context *ctx, int i){
+  valuearray_cursor *pCur = (valuearray_cursor*)cur;
+  sqlite3_valuearray *p = ((valuearray_vtab*)cur->pVtab)->pContent;
+  if( pCur->i>=0 && pCur->i<p->n ){
+    if( p->eType==SQLITE_FLOAT ){
+      sqlite3_result_double(ctx, p->aDouble[pCur->i]);
+    }else{
+      /* empty values may have no backing data but must not be NULL */
+      const char *z = p->zData ? p->zData+p->aOffset[pCur->i] : "";
+      int n = (int)(p->aOffset[pCur->i+1]-p->aOffset[pCur->i]);
+      if( p->eType==SQLITE_TEXT ){
+        sqlite3_result_text(ctx, z, n, SQLITE_TRANSIENT);
+      }else{
+        sqlite3_result_blob(ctx, z, n, SQLITE_TRANSIENT);
+      }
+    }
+  }
+  return SQLITE_OK;
+}
+
+/*
+** Retrieve the current rowid.
+*/
+static int valuearrayRowid(sqlite3_vtab_cursor *cur, sqlite_int64 *pRowid){
+  valuearray_cursor *pCur = (valuearray_cursor *)cur;
+  *pRowid = pCur->i;
+  return SQLITE_OK;
+}
+
+static int valuearrayEof(sqlite3_vtab_cursor *cur){
+  valuearray_cursor *pCur = (valuearray_cursor *)cur;
+  valuearray_vtab *pVtab = (valuearray_vtab *)cur->pVtab;
+  return pCur->i>=pVtab->pContent->n;
+}
+
+/*
+** Advance the cursor to the next row.
+*/
+static int valuearrayNext(sqlite3_vtab_cursor *cur){
+  valuearray_cursor *pCur = (valuearray_cursor *)cur;
+  pCur->i++;
+  return SQLITE_OK;
+}
+
+/*
+** Reset a valuearray table cursor.
+*/
+static int valuearrayFilter(
+  sqlite3_vtab_cursor *pVtabCursor,
+  int idxNum, const char *idxStr,
+  int argc, sqlite3_value **argv
+){
+  valuearray_cursor *pCur = (valuearray_cursor *)pVtabCursor;
+  pCur->i = 0;
+  return SQLITE_OK;
+}
+
+/*
+** Analyse the WHERE condition.
+*/
+static int valuearrayBestIndex(sqlite3_vtab *tab, sqlite3_index_info *pIdxInfo){
+  return SQLITE_OK;
+}
+
+static sqlite3_module valuearrayModule = {
+  0,                             /* iVersion */
+  valuearrayCreate,              /* xCreate - create a new virtual table */
+  valuearrayCreate,              /* xConnect - connect to an existing vtab */
+  valuearrayBestIndex,           /* xBestIndex - find the best query index */
+  valuearrayDestroy,             /* xDisconnect - disconnect a vtab */
+  valuearrayDestroy,             /* xDestroy - destroy a vtab */
+  valuearrayOpen,                /* xOpen - open a cursor */
+  valuearrayClose,               /* xClose - close a cursor */
+  valuearrayFilter,              /* xFilter - configure scan constraints */
+  valuearrayNext,                /* xNext - advance a cursor */
+  valuearrayEof,                 /* xEof */
+  valuearrayColumn,              /* xColumn - read data */
+  valuearrayRowid,               /* xRowid - read data */
+  0,                             /* xUpdate */
+  0,                             /* xBegin */
+  0,                             /* xSync */
+  0,                             /* xCommit */
+  0,                             /* xRollback */
+  0,                             /* xFindMethod */
+  0,                             /* xRename */
+};
+
+/*
+** Invoke this routine to create a specific instance of a valuearray object
+** of type eType (SQLITE_FLOAT, SQLITE_TEXT or SQLITE_BLOB).
+** The new valuearray object is returned by the 4th parameter.
+**
+** Each valuearray object corresponds to a virtual table in the TEMP table
+** with a name of zName.
+*/
+int sqlite3_valuearray_create(
+  sqlite3 *db,
+  const char *zName,
+  int eType,
+  sqlite3_valuearray **ppReturn
+){
+  int rc = SQLITE_OK;
+  sqlite3_valuearray *p;
+
+  *ppReturn = p = sqlite3_malloc( sizeof(*p) );
+  if( p==0 ){
+    return SQLITE_NOMEM;
+  }
+  memset(p, 0, sizeof(*p));
+  p->eType = eType;
+  rc = sqlite3_create_module_v2(db, zName, &valuearrayModule, p, sqlite3_free);
+  if( rc==SQLITE_OK ){
+    char *zSql;
+    zSql = sqlite3_mprintf("CREATE VIRTUAL TABLE temp.%Q USING %Q",
+                           zName, zName);
+    rc = sqlite3_exec(db, zSql, 0, 0, 0);
+    sqlite3_free(zSql);
+  }
+  return rc;
+}
+
+/*
+** Bind a new array of floats to a specific valuearray object.
+**
+** The array bound must be unchanged for the duration of
+** any query against the corresponding virtual table.
+*/
+int sqlite3_valuearray_bind_double(
+  sqlite3_valuearray *pArray,    /* The valuearray object to bind to */
+  int nElements,                 /* Number of elements in the array */
+  double *aElements              /* Content of the array */
+){
+  pArray->n = nElements;
+  pArray->aDouble = aElements;
+  return SQLITE_OK;
+}
+
+/*
+** Bind a new array of texts or blobs to a specific valuearray object.
+** aOffset must contain nElements+1 offsets.
+**
+** The data bound must be unchanged for the duration of
+** any query against the corresponding virtual table.
+*/
+int sqlite3_valuearray_bind_data(
+  sqlite3_valuearray *pArray,    /* The valuearray object to bind to */
+  int nElements,                 /* Number of elements in the array */
+  const char *zData,             /* Concatenated values */
+  sqlite3_int64 *aOffset         /* Offsets of values in zData */
+){
+  pArray->n = nElements;
+  pArray->zData = zData;
+  pArray->aOffset = aOffset;
+  return SQLITE_OK;
+}
@@ -0,0 +1,198 @@
+// Copyright 2010 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sqlite
+
+/*
+#include <sqlite3.h>
+#include <stdlib.h>
+
+// An sqlite3_valuearray is an abstract type to stores an instance of an array of values.
+typedef struct sqlite3_valuearray sqlite3_valuearray;
+int sqlite3_valuearray_bind_double(sqlite3_valuearray *pArray, int nElements, double *aElements);
+int sqlite3_valuearray_bind_data(sqlite3_valuearray *pArray, int nElements, const char *zData, sqlite3_int64 *aOffset);
+int sqlite3_valuearray_create(sqlite3 *db, const char *zName, int eType, sqlite3_valuearray **ppReturn);
+*/
+import "C"
+
+import (
+	"errors"
+	"fmt"
+	"unsafe"
+)
+
+// FloatArray is the float64 counterpart of IntArray.
+// The virtual table has one column named "value" with REAL affinity:
+//
+//	SELECT * FROM table WHERE x IN ex1;
+//
+// (See IntArray for usage)
+type FloatArray interface {
+	Bind(elements []float64)
+	Drop() error
+}
+
+// TextArray is the string counterpart of IntArray.
+// The virtual table has one column named "value" with TEXT affinity:
+//
+//	SELECT * FROM table WHERE name IN (SELECT value FROM names);
+//
+// (See IntArray for usage)
+type TextArray interface {
+	Bind(elements []string)
+	Drop() error
+}
+
+// BlobArray is the []byte counterpart of IntArray.
+// The virtual table has one column named "value" with BLOB (NONE) affinity.
+// (See IntArray for usage)
+type BlobArray interface {
+	Bind(elements [][]byte)
+	Drop() error
+}
+
+type valueArray struct {
+	c       *Conn
+	va      *C.sqlite3_valuearray
+	name    string
+	content interface{} // keeps bound data alive
+	offsets []int64
+}
+
+type floatArray struct {
+	*valueArray
+}
+
+type textArray struct {
+	*valueArray
+}
+
+type blobArray struct {
+	*valueArray
+}
+
+// CreateFloatArray create a specific instance of a float array object.
+// (See CreateIntArray)
+func (c *Conn) CreateFloatArray(name string) (FloatArray, error) {
+	va, err := c.createValueArray(name, C.SQLITE_FLOAT)
+	if err != nil {
+		return nil, err
+	}
+	return floatArray{va}, nil
+}
+
+// CreateTextArray create a specific instance of a text array object.
+// (See CreateIntArray)
+func (c *Conn) CreateTextArray(name string) (TextArray, error) {
+	va, err := c.createValueArray(name, C.SQLITE_TEXT)
+	if err != nil {
+		return nil, err
+	}
+	return textArray{va}, nil
+}
+
+// CreateBlobArray create a specific instance of a blob array object.
+// (See CreateIntArray)
+func (c *Conn) CreateBlobArray(name string) (BlobArray, error) {
+	va, err := c.createValueArray(name, C.SQLITE_BLOB)
+	if err != nil {
+		return nil, err
+	}
+	return blobArray{va}, nil
+}
+
+func (c *Conn) createValueArray(name string, eType C.int) (*valueArray, error) {
+	var va *C.sqlite3_valuearray
+	cname := C.CString(name)
+	rv := C.sqlite3_valuearray_create(c.db, cname, eType, &va)
+	C.free(unsafe.Pointer(cname))
+	if rv != C.SQLITE_OK {
+		return nil, Errno(rv)
+	}
+	if va == nil {
+		return nil, errors.New("sqlite succeeded without returning a valuearray")
+	}
+	return &valueArray{c: c, va: va, name: name}, nil
+}
+
+// Bind a new array of floats to a specific float array object.
+//
+// The array bound must be unchanged for the duration of
+// any query against the corresponding virtual table.
+func (m floatArray) Bind(elements []float64) {
+	if m.va == nil {
+		return
+	}
+	m.content = elements
+	var p *float64
+	if len(elements) > 0 {
+		p = &elements[0]
+	}
+	C.sqlite3_valuearray_bind_double(m.va, C.int(len(elements)), (*C.double)(unsafe.Pointer(p)))
+}
+
+// Bind a new array of strings to a specific text array object.
+// The strings are copied.
+func (m textArray) Bind(elements []string) {
+	if m.va == nil {
+		return
+	}
+	n := 0
+	for _, e := range elements {
+		n += len(e)
+	}
+	data := make([]byte, 0, n)
+	offsets := make([]int64, len(elements)+1)
+	for i, e := range elements {
+		data = append(data, e...)
+		offsets[i+1] = int64(len(data))
+	}
+	m.bindData(len(elements), data, offsets)
+}
+
+// Bind a new array of byte slices to a specific blob array object.
+// The byte slices are copied.
+func (m blobArray) Bind(elements [][]byte) {
+	if m.va == nil {
+		return
+	}
+	n := 0
+	for _, e := range elements {
+		n += len(e)
+	}
+	data := make([]byte, 0, n)
+	offsets := make([]int64, len(elements)+1)
+	for i, e := range elements {
+		data = append(data, e...)
+		offsets[i+1] = int64(len(data))
+	}
+	m.bindData(len(elements), data, offsets)
+}
+
+func (m *valueArray) bindData(n int, data []byte, offsets []int64) {
+	m.content = data
+	m.offsets = offsets
+	var p *byte
+	if len(data) > 0 {
+		p = &data[0]
+	}
+	C.sqlite3_valuearray_bind_data(m.va, C.int(n), (*C.char)(unsafe.Pointer(p)), (*C.sqlite3_int64)(unsafe.Pointer(&offsets[0])))
+}
+
+// Drop underlying virtual table.
+func (m *valueArray) Drop() error {
+	if m == nil {
+		return errors.New("nil sqlite valuearray")
+	}
+	if m.c == nil {
+		return nil
+	}
+	err := m.c.FastExec(fmt.Sprintf(`DROP TABLE temp."%s"`, escapeQuote(m.name)))
+	if err != nil {
+		return err
+	}
+	m.c = nil
+	m.va = nil
+	return nil
+}
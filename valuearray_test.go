@@ -0,0 +1,62 @@
+// Copyright 2010 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sqlite_test
+
+import (
+	"testing"
+
+	"github.com/bmizerany/assert"
+	. "github.com/gwenn/gosqlite"
+)
+
+func TestValueArrayModules(t *testing.T) {
+	db := open(t)
+	defer checkClose(db, t)
+
+	err := db.FastExec(`CREATE TABLE t (name TEXT, score REAL, data BLOB);
+		INSERT INTO t VALUES ('a', 1.5, x'01'), ('b', 2.5, x''), ('', 3.5, x'0203');`)
+	checkNoError(t, err, "%s")
+
+	names, err := db.CreateTextArray("names")
+	checkNoError(t, err, "error creating text array: %s")
+	scores, err := db.CreateFloatArray("scores")
+	checkNoError(t, err, "error creating float array: %s")
+	blobs, err := db.CreateBlobArray("blobs")
+	checkNoError(t, err, "error creating blob array: %s")
+
+	s, err := db.Prepare(`SELECT count(*) FROM t WHERE name IN (SELECT value FROM names)
+		AND score IN scores AND data IN blobs`)
+	checkNoError(t, err, "%s")
+	defer checkFinalize(s, t)
+
+	var count int
+	names.Bind([]string{"a", "b", ""})
+	scores.Bind([]float64{1.5, 2.5, 3.5})
+	blobs.Bind([][]byte{{1}, {}, {2, 3}})
+	err = s.Select(func(s *Stmt) error {
+		return s.Scan(&count)
+	})
+	checkNoError(t, err, "%s")
+	assert.Equal(t, 3, count)
+
+	names.Bind([]string{"b", "c"})
+	scores.Bind(nil)
+	err = s.Select(func(s *Stmt) error {
+		return s.Scan(&count)
+	})
+	checkNoError(t, err, "%s")
+	assert.Equal(t, 0, count)
+
+	scores.Bind([]float64{2.5})
+	err = s.Select(func(s *Stmt) error {
+		return s.Scan(&count)
+	})
+	checkNoError(t, err, "%s")
+	assert.Equal(t, 1, count)
+
+	checkNoError(t, names.Drop(), "%s")
+	checkNoError(t, scores.Drop(), "%s")
+	checkNoError(t, blobs.Drop(), "%s")
+}
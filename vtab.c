@@ -38,8 +38,20 @@ static inline int cXConnect(sqlite3 *db, void *pAux, int argc, const char *const
 	return cXInit(db, pAux, argc, argv, ppVTab, pzErr, 0);
 }
 
-static inline int cXBestIndex(sqlite3_vtab *pVTab, sqlite3_index_info *info) {
-	// TODO
+static int setVTabErrMsg(sqlite3_vtab *pVTab, char *pzErr) {
+	if (pVTab->zErrMsg)
+		sqlite3_free(pVTab->zErrMsg);
+	pVTab->zErrMsg = pzErr;
+	return SQLITE_ERROR;
+}
+
+static int cXBestIndex(sqlite3_vtab *pVTab, sqlite3_index_info *info) {
+	int rc = SQLITE_OK;
+	char *pzErr = goVBestIndex(((goVTab*)pVTab)->vTab, info, &rc);
+	if (pzErr) {
+		setVTabErrMsg(pVTab, pzErr);
+		return rc;
+	}
 	return SQLITE_OK;
 }
 
@@ -83,11 +95,8 @@ static int cXOpen(sqlite3_vtab *pVTab, sqlite3_vtab_cursor **ppCursor) {
 	return SQLITE_OK;
 }
 
-static int setErrMsg(sqlite3_vtab_cursor *pCursor, char *pzErr) {
-	if (pCursor->pVtab->zErrMsg)
-		sqlite3_free(pCursor->pVtab->zErrMsg);
-	pCursor->pVtab->zErrMsg = pzErr;
-	return SQLITE_ERROR;
+static inline int setErrMsg(sqlite3_vtab_cursor *pCursor, char *pzErr) {
+	return setVTabErrMsg(pCursor->pVtab, pzErr);
 }
 
 static int cXClose(sqlite3_vtab_cursor *pCursor) {
@@ -99,8 +108,7 @@ static int cXClose(sqlite3_vtab_cursor *pCursor) {
 	return SQLITE_OK;
 }
 static int cXFilter(sqlite3_vtab_cursor *pCursor, int idxNum, const char *idxStr, int argc, sqlite3_value **argv) {
-	// TODO idxNum, idxStr, argc, argv are useless when cXBestIndex is empty
-	char *pzErr = goVFilter(((goVTabCursor*)pCursor)->vTabCursor);
+	char *pzErr = goVFilter(((goVTabCursor*)pCursor)->vTabCursor, idxNum, (char*)idxStr, argc, argv);
 	if (pzErr) {
 		return setErrMsg(pCursor, pzErr);
 	}
@@ -131,34 +139,177 @@ static int cXRowid(sqlite3_vtab_cursor *pCursor, sqlite3_int64 *pRowid) {
 	return SQLITE_OK;
 }
 
-static sqlite3_module goModule = {
-	0,                       /* iVersion */
-	cXCreate,                /* xCreate - create a table */
-	cXConnect,               /* xConnect - connect to an existing table */
-	cXBestIndex,             /* xBestIndex - Determine search strategy */
-	cXDisconnect,            /* xDisconnect - Disconnect from a table */
-	cXDestroy,               /* xDestroy - Drop a table */
-	cXOpen,                  /* xOpen - open a cursor */
-	cXClose,                 /* xClose - close a cursor */
-	cXFilter,                /* xFilter - configure scan constraints */
-	cXNext,                  /* xNext - advance a cursor */
-	cXEof,                   /* xEof */
-	cXColumn,                /* xColumn - read data */
-	cXRowid,                 /* xRowid - read data */
-// TODO
-	0,                       /* xUpdate - write data */
-	0,                       /* xBegin - begin transaction */
-	0,                       /* xSync - sync transaction */
-	0,                       /* xCommit - commit transaction */
-	0,                       /* xRollback - rollback transaction */
-	0,                       /* xFindFunction - function overloading */
-	0,                       /* xRename - rename the table */
-	0,                       /* xSavepoint */
-	0,                       /* xRelease */
-	0	                       /* xRollbackTo */
+static int cXUpdate(sqlite3_vtab *pVTab, int argc, sqlite3_value **argv, sqlite3_int64 *pRowid) {
+	int rc = SQLITE_OK;
+	char *pzErr = goVUpdate(((goVTab*)pVTab)->vTab, argc, argv, pRowid, &rc);
+	if (pzErr) {
+		setVTabErrMsg(pVTab, pzErr);
+		return rc;
+	}
+	return SQLITE_OK;
+}
+
+static int cXBegin(sqlite3_vtab *pVTab) {
+	char *pzErr = goVBegin(((goVTab*)pVTab)->vTab);
+	if (pzErr) {
+		return setVTabErrMsg(pVTab, pzErr);
+	}
+	return SQLITE_OK;
+}
+static int cXSync(sqlite3_vtab *pVTab) {
+	char *pzErr = goVSync(((goVTab*)pVTab)->vTab);
+	if (pzErr) {
+		return setVTabErrMsg(pVTab, pzErr);
+	}
+	return SQLITE_OK;
+}
+static int cXCommit(sqlite3_vtab *pVTab) {
+	char *pzErr = goVCommit(((goVTab*)pVTab)->vTab);
+	if (pzErr) {
+		return setVTabErrMsg(pVTab, pzErr);
+	}
+	return SQLITE_OK;
+}
+static int cXRollback(sqlite3_vtab *pVTab) {
+	char *pzErr = goVRollback(((goVTab*)pVTab)->vTab);
+	if (pzErr) {
+		return setVTabErrMsg(pVTab, pzErr);
+	}
+	return SQLITE_OK;
+}
+static int cXSavepoint(sqlite3_vtab *pVTab, int i) {
+	char *pzErr = goVSavepoint(((goVTab*)pVTab)->vTab, i);
+	if (pzErr) {
+		return setVTabErrMsg(pVTab, pzErr);
+	}
+	return SQLITE_OK;
+}
+static int cXReleaseSavepoint(sqlite3_vtab *pVTab, int i) {
+	char *pzErr = goVReleaseSavepoint(((goVTab*)pVTab)->vTab, i);
+	if (pzErr) {
+		return setVTabErrMsg(pVTab, pzErr);
+	}
+	return SQLITE_OK;
+}
+static int cXRollbackTo(sqlite3_vtab *pVTab, int i) {
+	char *pzErr = goVRollbackTo(((goVTab*)pVTab)->vTab, i);
+	if (pzErr) {
+		return setVTabErrMsg(pVTab, pzErr);
+	}
+	return SQLITE_OK;
+}
+
+extern void goSqlite3ScalarFunction(sqlite3_context *ctx, int argc, sqlite3_value **argv);
+
+static int cXFindFunction(sqlite3_vtab *pVTab, int nArg, const char *zName, void (**pxFunc)(sqlite3_context*,int,sqlite3_value**), void **ppArg) {
+	void *udf = goVFindFunction(((goVTab*)pVTab)->vTab, nArg, (char*)zName);
+	if (!udf) {
+		return 0;
+	}
+	*pxFunc = goSqlite3ScalarFunction;
+	*ppArg = udf;
+	return 1;
+}
+
+static int cXRename(sqlite3_vtab *pVTab, const char *zNew) {
+	char *pzErr = goVRename(((goVTab*)pVTab)->vTab, (char*)zNew);
+	if (pzErr) {
+		return setVTabErrMsg(pVTab, pzErr);
+	}
+	return SQLITE_OK;
+}
+
+// xShadowName has no client data so each module implementing ShadowNamer
+// is registered with its own copy of goModule whose callback identifies the module by its slot.
+#define GO_SHADOW_NAME(i) static int cXShadowName##i(const char *zSuffix) { return goMShadowName(i, (char*)zSuffix); }
+GO_SHADOW_NAME(0)
+GO_SHADOW_NAME(1)
+GO_SHADOW_NAME(2)
+GO_SHADOW_NAME(3)
+GO_SHADOW_NAME(4)
+GO_SHADOW_NAME(5)
+GO_SHADOW_NAME(6)
+GO_SHADOW_NAME(7)
+GO_SHADOW_NAME(8)
+GO_SHADOW_NAME(9)
+GO_SHADOW_NAME(10)
+GO_SHADOW_NAME(11)
+GO_SHADOW_NAME(12)
+GO_SHADOW_NAME(13)
+GO_SHADOW_NAME(14)
+GO_SHADOW_NAME(15)
+
+static int (*const goShadowNames[])(const char*) = {
+	cXShadowName0, cXShadowName1, cXShadowName2, cXShadowName3,
+	cXShadowName4, cXShadowName5, cXShadowName6, cXShadowName7,
+	cXShadowName8, cXShadowName9, cXShadowName10, cXShadowName11,
+	cXShadowName12, cXShadowName13, cXShadowName14, cXShadowName15,
 };
+#define GO_MAX_SHADOW_NAMERS (sizeof(goShadowNames) / sizeof(goShadowNames[0]))
 
+static int cXIntegrity(sqlite3_vtab *pVTab, const char *zSchema, const char *zTabName, int mFlags, char **pzErr) {
+	char *zErr = goVIntegrity(((goVTab*)pVTab)->vTab, (char*)zSchema, (char*)zTabName, mFlags, pzErr);
+	if (zErr) {
+		return setVTabErrMsg(pVTab, zErr);
+	}
+	return SQLITE_OK;
+}
 
-int goSqlite3CreateModule(sqlite3 *db, const char *zName, void *pClientData) {
-	return sqlite3_create_module_v2(db, zName, &goModule, pClientData, goMDestroy);
+// iVersion is set by goSqlite3InitModules according to the linked library version.
+#define GO_MODULE(xCreate) { \
+	0,                       /* iVersion */ \
+	xCreate,                 /* xCreate - create a table (0 for eponymous-only modules) */ \
+	cXConnect,               /* xConnect - connect to an existing table */ \
+	cXBestIndex,             /* xBestIndex - Determine search strategy */ \
+	cXDisconnect,            /* xDisconnect - Disconnect from a table */ \
+	cXDestroy,               /* xDestroy - Drop a table */ \
+	cXOpen,                  /* xOpen - open a cursor */ \
+	cXClose,                 /* xClose - close a cursor */ \
+	cXFilter,                /* xFilter - configure scan constraints */ \
+	cXNext,                  /* xNext - advance a cursor */ \
+	cXEof,                   /* xEof */ \
+	cXColumn,                /* xColumn - read data */ \
+	cXRowid,                 /* xRowid - read data */ \
+	cXUpdate,                /* xUpdate - write data */ \
+	cXBegin,                 /* xBegin - begin transaction */ \
+	cXSync,                  /* xSync - sync transaction */ \
+	cXCommit,                /* xCommit - commit transaction */ \
+	cXRollback,              /* xRollback - rollback transaction */ \
+	cXFindFunction,          /* xFindFunction - function overloading */ \
+	cXRename,                /* xRename - rename the table */ \
+	cXSavepoint,             /* xSavepoint */ \
+	cXReleaseSavepoint,      /* xRelease */ \
+	cXRollbackTo,            /* xRollbackTo */ \
+	0,                       /* xShadowName - see goShadowModules */ \
+	cXIntegrity,             /* xIntegrity */ \
+}
+
+static sqlite3_module goModule = GO_MODULE(cXCreate);
+static sqlite3_module goEponymousOnlyModule = GO_MODULE(0);
+static sqlite3_module goShadowModules[GO_MAX_SHADOW_NAMERS];
+static sqlite3_module goEponymousOnlyShadowModules[GO_MAX_SHADOW_NAMERS];
+
+// goSqlite3InitModules must be called once before any module registration.
+// xShadowName is only used by SQLite >= 3.26 (iVersion 3) and xIntegrity by SQLite >= 3.44 (iVersion 4).
+void goSqlite3InitModules(void) {
+	int v = sqlite3_libversion_number();
+	int iVersion = v >= 3044000 ? 4 : v >= 3026000 ? 3 : 2;
+	goModule.iVersion = iVersion;
+	goEponymousOnlyModule.iVersion = iVersion;
+	for (int i = 0; i < GO_MAX_SHADOW_NAMERS; i++) {
+		goShadowModules[i] = goModule;
+		goShadowModules[i].xShadowName = goShadowNames[i];
+		goEponymousOnlyShadowModules[i] = goEponymousOnlyModule;
+		goEponymousOnlyShadowModules[i].xShadowName = goShadowNames[i];
+	}
+}
+
+int goSqlite3CreateModule(sqlite3 *db, const char *zName, void *pClientData, int eponymousOnly, int iShadowNamer) {
+	sqlite3_module *pModule;
+	if (iShadowNamer >= 0) {
+		pModule = eponymousOnly ? &goEponymousOnlyShadowModules[iShadowNamer] : &goShadowModules[iShadowNamer];
+	} else {
+		pModule = eponymousOnly ? &goEponymousOnlyModule : &goModule;
+	}
+	return sqlite3_create_module_v2(db, zName, pModule, pClientData, goMDestroy);
 }
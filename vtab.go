@@ -8,27 +8,37 @@ package sqlite
 #include <sqlite3.h>
 #include <stdlib.h>
 
-int goSqlite3CreateModule(sqlite3 *db, const char *zName, void *pClientData);
+void goSqlite3InitModules(void);
+int goSqlite3CreateModule(sqlite3 *db, const char *zName, void *pClientData, int eponymousOnly, int iShadowNamer);
 */
 import "C"
 
 import (
 	"fmt"
 	"reflect"
+	"sync"
 	"unsafe"
 )
 
 type sqliteModule struct {
 	c      *Conn
 	name   string
-	module Module
+	module EponymousModule
 	vts    map[*sqliteVTab]struct{}
+	shadow int // shadowNamers slot or -1
 }
 
 type sqliteVTab struct {
 	module *sqliteModule
 	vTab   VTab
 	vtcs   map[*sqliteVTabCursor]struct{}
+	udfs   map[vtabFunctionKey]*sqliteFunction
+}
+
+// vtabFunctionKey identifies a function returned by VTabFunctionFinder.
+type vtabFunctionKey struct {
+	name string
+	nArg int
 }
 
 type sqliteVTabCursor struct {
@@ -53,7 +63,7 @@ func goMInit(db, pClientData unsafe.Pointer, argc int, argv **C.char, pzErr **C.
 	var vTab VTab
 	var err error
 	if isCreate == 1 {
-		vTab, err = m.module.Create(m.c, args)
+		vTab, err = m.module.(Module).Create(m.c, args)
 	} else {
 		vTab, err = m.module.Connect(m.c, args)
 	}
@@ -62,7 +72,7 @@ func goMInit(db, pClientData unsafe.Pointer, argc int, argv **C.char, pzErr **C.
 		*pzErr = mPrintf("%s", err.Error())
 		return nil
 	}
-	vt := &sqliteVTab{m, vTab, nil}
+	vt := &sqliteVTab{m, vTab, nil, nil}
 	// prevents 'vt' from being gced
 	if m.vts == nil {
 		m.vts = make(map[*sqliteVTab]struct{})
@@ -86,6 +96,7 @@ func goVRelease(pVTab unsafe.Pointer, isDestroy int) *C.char {
 	}
 	// TODO Check vt.vtcs is empty
 	vt.vtcs = nil
+	vt.udfs = nil
 	delete(vt.module.vts, vt)
 	return nil
 }
@@ -125,19 +136,357 @@ func goMDestroy(pClientData unsafe.Pointer) {
 	m.module.DestroyModule()
 	// TODO Check m.vts is empty
 	m.vts = nil
+	if m.shadow >= 0 {
+		releaseShadowNamer(m.shadow)
+	}
 	delete(m.c.modules, m.name)
 }
 
+//export goVBestIndex
+func goVBestIndex(pVTab unsafe.Pointer, info *C.sqlite3_index_info, pRc *C.int) *C.char {
+	vt := (*sqliteVTab)(pVTab)
+	ii := &IndexInfo{
+		ColUsed:       uint64(info.colUsed),
+		EstimatedCost: float64(info.estimatedCost),
+		EstimatedRows: int64(info.estimatedRows),
+	}
+	if nc := int(info.nConstraint); nc > 0 {
+		constraints := (*[1 << 20]C.struct_sqlite3_index_constraint)(unsafe.Pointer(info.aConstraint))[:nc:nc]
+		ii.Constraints = make([]IndexConstraint, nc)
+		for i, c := range constraints {
+			ii.Constraints[i] = IndexConstraint{Column: int(c.iColumn), Op: ConstraintOp(c.op), Usable: c.usable != 0,
+				Collation: C.GoString(C.sqlite3_vtab_collation(info, C.int(i)))}
+		}
+		ii.ConstraintUsages = make([]IndexConstraintUsage, nc)
+	}
+	if no := int(info.nOrderBy); no > 0 {
+		orderBys := (*[1 << 20]C.struct_sqlite3_index_orderby)(unsafe.Pointer(info.aOrderBy))[:no:no]
+		ii.OrderBys = make([]IndexOrderBy, no)
+		for i, o := range orderBys {
+			ii.OrderBys[i] = IndexOrderBy{Column: int(o.iColumn), Desc: o.desc != 0}
+		}
+	}
+	err := vt.vTab.BestIndex(ii)
+	if err != nil {
+		*pRc = errCode(err)
+		return mPrintf("%s", err.Error())
+	}
+	if nc := int(info.nConstraint); nc > 0 {
+		usages := (*[1 << 20]C.struct_sqlite3_index_constraint_usage)(unsafe.Pointer(info.aConstraintUsage))[:nc:nc]
+		for i, u := range ii.ConstraintUsages {
+			if i >= nc {
+				break
+			}
+			usages[i].argvIndex = C.int(u.ArgvIndex)
+			usages[i].omit = C.uchar(btocint(u.Omit))
+		}
+	}
+	info.idxNum = C.int(ii.IdxNum)
+	if len(ii.IdxStr) > 0 {
+		info.idxStr = mPrintf("%s", ii.IdxStr)
+		info.needToFreeIdxStr = 1
+	}
+	info.orderByConsumed = btocint(ii.OrderByConsumed)
+	info.estimatedCost = C.double(ii.EstimatedCost)
+	info.estimatedRows = C.sqlite3_int64(ii.EstimatedRows)
+	info.idxFlags = C.int(ii.IdxFlags)
+	return nil
+}
+
+// goValues converts argv values to Go values (see FunctionContext.Value).
+func goValues(argc int, argv unsafe.Pointer) []interface{} {
+	if argc == 0 {
+		return nil
+	}
+	c := &FunctionContext{argv: (**C.sqlite3_value)(argv)}
+	values := make([]interface{}, argc)
+	for i := range values {
+		values[i] = c.Value(i)
+	}
+	return values
+}
+
 //export goVFilter
-func goVFilter(pCursor unsafe.Pointer) *C.char {
+func goVFilter(pCursor unsafe.Pointer, idxNum int, idxStr *C.char, argc int, argv unsafe.Pointer) *C.char {
 	vtc := (*sqliteVTabCursor)(pCursor)
-	err := vtc.vTabCursor.Filter()
+	var err error
+	if f, ok := vtc.vTabCursor.(VTabCursorValueFilter); ok {
+		err = f.FilterValues(idxNum, C.GoString(idxStr), goRawValues(argc, argv))
+	} else {
+		err = vtc.vTabCursor.Filter(idxNum, C.GoString(idxStr), goValues(argc, argv))
+	}
+	if err != nil {
+		return mPrintf("%s", err.Error())
+	}
+	return nil
+}
+
+//export goVUpdate
+func goVUpdate(pVTab unsafe.Pointer, argc int, argv unsafe.Pointer, pRowid *C.sqlite3_int64, pRc *C.int) *C.char {
+	vt := (*sqliteVTab)(pVTab)
+	if u, ok := vt.vTab.(VTabValueUpdater); ok {
+		r, err := u.UpdateValues(goRawValues(argc, argv))
+		if err != nil {
+			*pRc = errCode(err)
+			return mPrintf("%s", err.Error())
+		}
+		*pRowid = C.sqlite3_int64(r)
+		return nil
+	}
+	u, ok := vt.vTab.(VTabUpdater)
+	if !ok {
+		*pRc = C.SQLITE_READONLY
+		return mPrintf("%s", "virtual table is read-only")
+	}
+	values := goValues(argc, argv)
+	mismatch := func() *C.char {
+		*pRc = C.SQLITE_MISMATCH
+		return mPrintf("%s", "datatype mismatch: rowid must be an integer")
+	}
+	var err error
+	if argc == 1 {
+		oldRowid, ok := values[0].(int64)
+		if !ok {
+			return mismatch()
+		}
+		err = u.Delete(oldRowid)
+	} else if values[0] == nil {
+		var rowid *int64
+		if values[1] != nil {
+			r, ok := values[1].(int64)
+			if !ok {
+				return mismatch()
+			}
+			rowid = &r
+		}
+		var r int64
+		if r, err = u.Insert(rowid, values[2:]); err == nil {
+			*pRowid = C.sqlite3_int64(r)
+		}
+	} else {
+		oldRowid, ok := values[0].(int64)
+		if !ok {
+			return mismatch()
+		}
+		newRowid, ok := values[1].(int64)
+		if !ok {
+			return mismatch()
+		}
+		err = u.Update(oldRowid, newRowid, values[2:])
+	}
+	if err != nil {
+		*pRc = errCode(err)
+		return mPrintf("%s", err.Error())
+	}
+	return nil
+}
+
+//export goVBegin
+func goVBegin(pVTab unsafe.Pointer) *C.char {
+	vt := (*sqliteVTab)(pVTab)
+	if t, ok := vt.vTab.(VTabTransaction); ok {
+		if err := t.Begin(); err != nil {
+			return mPrintf("%s", err.Error())
+		}
+	}
+	return nil
+}
+
+//export goVSync
+func goVSync(pVTab unsafe.Pointer) *C.char {
+	vt := (*sqliteVTab)(pVTab)
+	if t, ok := vt.vTab.(VTabTransaction); ok {
+		if err := t.Sync(); err != nil {
+			return mPrintf("%s", err.Error())
+		}
+	}
+	return nil
+}
+
+//export goVCommit
+func goVCommit(pVTab unsafe.Pointer) *C.char {
+	vt := (*sqliteVTab)(pVTab)
+	if t, ok := vt.vTab.(VTabTransaction); ok {
+		if err := t.Commit(); err != nil {
+			return mPrintf("%s", err.Error())
+		}
+	}
+	return nil
+}
+
+//export goVRollback
+func goVRollback(pVTab unsafe.Pointer) *C.char {
+	vt := (*sqliteVTab)(pVTab)
+	if t, ok := vt.vTab.(VTabTransaction); ok {
+		if err := t.Rollback(); err != nil {
+			return mPrintf("%s", err.Error())
+		}
+	}
+	return nil
+}
+
+//export goVSavepoint
+func goVSavepoint(pVTab unsafe.Pointer, i int) *C.char {
+	vt := (*sqliteVTab)(pVTab)
+	if sp, ok := vt.vTab.(VTabSavepointer); ok {
+		if err := sp.Savepoint(i); err != nil {
+			return mPrintf("%s", err.Error())
+		}
+	}
+	return nil
+}
+
+//export goVReleaseSavepoint
+func goVReleaseSavepoint(pVTab unsafe.Pointer, i int) *C.char {
+	vt := (*sqliteVTab)(pVTab)
+	if sp, ok := vt.vTab.(VTabSavepointer); ok {
+		if err := sp.Release(i); err != nil {
+			return mPrintf("%s", err.Error())
+		}
+	}
+	return nil
+}
+
+//export goVRollbackTo
+func goVRollbackTo(pVTab unsafe.Pointer, i int) *C.char {
+	vt := (*sqliteVTab)(pVTab)
+	if sp, ok := vt.vTab.(VTabSavepointer); ok {
+		if err := sp.RollbackTo(i); err != nil {
+			return mPrintf("%s", err.Error())
+		}
+	}
+	return nil
+}
+
+//export goVFindFunction
+func goVFindFunction(pVTab unsafe.Pointer, nArg int, zName *C.char) unsafe.Pointer {
+	vt := (*sqliteVTab)(pVTab)
+	ff, ok := vt.vTab.(VTabFunctionFinder)
+	if !ok {
+		return nil
+	}
+	name := C.GoString(zName)
+	// Prepared statements keep a pointer to the function object until the virtual table is disconnected
+	// so the same object is returned for the same function and it is never replaced.
+	key := vtabFunctionKey{name, nArg}
+	if udf, ok := vt.udfs[key]; ok {
+		return unsafe.Pointer(udf)
+	}
+	f := ff.FindFunction(nArg, name)
+	if f == nil {
+		return nil
+	}
+	// To make sure it is not gced, keep a reference in the virtual table.
+	udf := &sqliteFunction{scalar: f, scalarCtxs: make(map[*ScalarContext]struct{})}
+	if vt.udfs == nil {
+		vt.udfs = make(map[vtabFunctionKey]*sqliteFunction)
+	}
+	vt.udfs[key] = udf
+	return unsafe.Pointer(udf)
+}
+
+//export goVRename
+func goVRename(pVTab unsafe.Pointer, zNew *C.char) *C.char {
+	vt := (*sqliteVTab)(pVTab)
+	if r, ok := vt.vTab.(VTabRenamer); ok {
+		if err := r.Rename(C.GoString(zNew)); err != nil {
+			return mPrintf("%s", err.Error())
+		}
+	}
+	return nil
+}
+
+func init() {
+	C.goSqlite3InitModules()
+}
+
+// maxShadowNamers must match the number of xShadowName callbacks in vtab.c (goShadowNames).
+const maxShadowNamers = 16
+
+// xShadowName has no client data so each module implementing ShadowNamer
+// is registered with its own xShadowName callback which passes the module slot.
+// Modules registered with the same name (on different connections) share their slot.
+var shadowNamers struct {
+	sync.Mutex
+	slots [maxShadowNamers]struct {
+		name string
+		sn   ShadowNamer
+		refs int
+	}
+}
+
+// acquireShadowNamer returns the slot used by the module name or -1 if there is no free slot.
+func acquireShadowNamer(name string, sn ShadowNamer) int {
+	shadowNamers.Lock()
+	defer shadowNamers.Unlock()
+	free := -1
+	for i := range shadowNamers.slots {
+		slot := &shadowNamers.slots[i]
+		if slot.refs > 0 && slot.name == name {
+			slot.refs++
+			return i
+		} else if slot.refs == 0 && free < 0 {
+			free = i
+		}
+	}
+	if free >= 0 {
+		shadowNamers.slots[free].name, shadowNamers.slots[free].sn, shadowNamers.slots[free].refs = name, sn, 1
+	}
+	return free
+}
+
+func releaseShadowNamer(i int) {
+	shadowNamers.Lock()
+	defer shadowNamers.Unlock()
+	slot := &shadowNamers.slots[i]
+	if slot.refs--; slot.refs == 0 {
+		slot.name, slot.sn = "", nil
+	}
+}
+
+//export goMShadowName
+func goMShadowName(i C.int, zSuffix *C.char) C.int {
+	shadowNamers.Lock()
+	sn := shadowNamers.slots[i].sn
+	shadowNamers.Unlock()
+	if sn != nil && sn.ShadowName(C.GoString(zSuffix)) {
+		return 1
+	}
+	return 0
+}
+
+//export goVIntegrity
+func goVIntegrity(pVTab unsafe.Pointer, zSchema, zTabName *C.char, mFlags int, pzErr **C.char) *C.char {
+	vt := (*sqliteVTab)(pVTab)
+	ic, ok := vt.vTab.(VTabIntegrityChecker)
+	if !ok {
+		return nil
+	}
+	problem, err := ic.Integrity(C.GoString(zSchema), C.GoString(zTabName), mFlags&1 != 0)
 	if err != nil {
 		return mPrintf("%s", err.Error())
 	}
+	if len(problem) > 0 {
+		*pzErr = mPrintf("%s", problem)
+	}
 	return nil
 }
 
+// errCode returns the SQLite result code matching err (SQLITE_ERROR by default).
+func errCode(err error) C.int {
+	switch e := err.(type) {
+	case Errno:
+		if e > 0 {
+			return C.int(e)
+		}
+	case ConnError:
+		if e.code > 0 {
+			return C.int(e.code)
+		}
+	}
+	return C.SQLITE_ERROR
+}
+
 //export goVNext
 func goVNext(pCursor unsafe.Pointer) *C.char {
 	vtc := (*sqliteVTabCursor)(pCursor)
@@ -176,6 +525,23 @@ func goVRowid(pCursor unsafe.Pointer, pRowid *C.sqlite3_int64) *C.char {
 	return nil
 }
 
+// EponymousModule is a "virtual table module" that can only be used as an eponymous virtual table
+// (or as a table-valued function when HIDDEN columns are declared).
+// (See http://sqlite.org/vtab.html#epoonlyvtab and http://sqlite.org/vtab.html#tabfunc2)
+type EponymousModule interface {
+	Connect(c *Conn, args []string) (VTab, error) // See http://sqlite.org/vtab.html#xconnect
+	DestroyModule()                               // See http://sqlite.org/c3ref/create_module.html
+}
+
+// ShadowNamer is implemented by modules storing their content in shadow tables
+// (named "<virtual table name>_<suffix>") to protect them against corruption (SQLite >= 3.26).
+// Modules registered with the same name on different connections must recognize the same suffixes
+// (only the first registered one is used) and at most 16 distinct module names can be registered at the same time.
+// (See http://sqlite.org/vtab.html#the_xshadowname_method)
+type ShadowNamer interface {
+	ShadowName(suffix string) bool
+}
+
 // Module is a "virtual table module", it defines the implementation of a virtual tables.
 // (See http://sqlite.org/c3ref/module.html)
 type Module interface {
@@ -184,46 +550,207 @@ type Module interface {
 	DestroyModule()                               // See http://sqlite.org/c3ref/create_module.html
 }
 
+// ConstraintOp enumerates virtual table constraint operator codes
+// (See http://sqlite.org/c3ref/c_index_constraint_eq.html)
+type ConstraintOp uint8
+
+// Virtual table constraint operator codes
+const (
+	OpEq        ConstraintOp = C.SQLITE_INDEX_CONSTRAINT_EQ
+	OpGt        ConstraintOp = C.SQLITE_INDEX_CONSTRAINT_GT
+	OpLe        ConstraintOp = C.SQLITE_INDEX_CONSTRAINT_LE
+	OpLt        ConstraintOp = C.SQLITE_INDEX_CONSTRAINT_LT
+	OpGe        ConstraintOp = C.SQLITE_INDEX_CONSTRAINT_GE
+	OpMatch     ConstraintOp = C.SQLITE_INDEX_CONSTRAINT_MATCH
+	OpLike      ConstraintOp = C.SQLITE_INDEX_CONSTRAINT_LIKE
+	OpGlob      ConstraintOp = C.SQLITE_INDEX_CONSTRAINT_GLOB
+	OpRegexp    ConstraintOp = C.SQLITE_INDEX_CONSTRAINT_REGEXP
+	OpNe        ConstraintOp = C.SQLITE_INDEX_CONSTRAINT_NE
+	OpIsNot     ConstraintOp = C.SQLITE_INDEX_CONSTRAINT_ISNOT
+	OpIsNotNull ConstraintOp = C.SQLITE_INDEX_CONSTRAINT_ISNOTNULL
+	OpIsNull    ConstraintOp = C.SQLITE_INDEX_CONSTRAINT_ISNULL
+	OpIs        ConstraintOp = C.SQLITE_INDEX_CONSTRAINT_IS
+)
+
+// IndexScanUnique is the IndexInfo.IdxFlags flag telling that the scan visits at most one row.
+// (See http://sqlite.org/c3ref/c_index_scan_unique.html)
+const IndexScanUnique = C.SQLITE_INDEX_SCAN_UNIQUE
+
+// IndexConstraint is a WHERE clause term that may be used by the virtual table.
+type IndexConstraint struct {
+	Column    int          // Column constrained. -1 for ROWID
+	Op        ConstraintOp // Constraint operator
+	Usable    bool         // True if this constraint is usable
+	Collation string       // Name of the collating sequence used to evaluate the constraint (See http://sqlite.org/c3ref/vtab_collation.html)
+}
+
+// IndexOrderBy is an ORDER BY clause term.
+type IndexOrderBy struct {
+	Column int  // Column number
+	Desc   bool // True for DESC. False for ASC.
+}
+
+// IndexConstraintUsage tells how the matching IndexConstraint is used by the virtual table.
+type IndexConstraintUsage struct {
+	ArgvIndex int  // if >0, constraint is part of argv to VTabCursor.Filter
+	Omit      bool // Do not code a test for this constraint
+}
+
+// IndexInfo is used to pass information into and receive the reply from VTab.BestIndex.
+// ConstraintUsages has the same length as Constraints.
+// (See http://sqlite.org/c3ref/index_info.html)
+type IndexInfo struct {
+	// Inputs
+	Constraints []IndexConstraint // Table of WHERE clause constraints
+	OrderBys    []IndexOrderBy    // The ORDER BY clause
+	ColUsed     uint64            // Mask of columns used by statement
+	// Outputs
+	ConstraintUsages []IndexConstraintUsage
+	IdxNum           int     // Number used to identify the index
+	IdxStr           string  // String used to identify the index
+	OrderByConsumed  bool    // True if output is already ordered
+	EstimatedCost    float64 // Estimated cost of using this index
+	EstimatedRows    int64   // Estimated number of rows returned
+	IdxFlags         int     // Mask of IndexScan* flags
+}
+
 // VTab describes a particular instance of the virtual table.
 // (See http://sqlite.org/c3ref/vtab.html)
 type VTab interface {
-	BestIndex( /*sqlite3_index_info**/ ) error // See http://sqlite.org/vtab.html#xbestindex
-	Disconnect() error                         // See http://sqlite.org/vtab.html#xdisconnect
-	Destroy() error                            // See http://sqlite.org/vtab.html#sqlite3_module.xDestroy
-	Open() (VTabCursor, error)                 // See http://sqlite.org/vtab.html#xopen
+	// ErrConstraint can be returned to reject a plan with unusable constraints (SQLite >= 3.26).
+	BestIndex(info *IndexInfo) error // See http://sqlite.org/vtab.html#xbestindex
+	Disconnect() error               // See http://sqlite.org/vtab.html#xdisconnect
+	Destroy() error                  // See http://sqlite.org/vtab.html#sqlite3_module.xDestroy
+	Open() (VTabCursor, error)       // See http://sqlite.org/vtab.html#xopen
 }
 
-// VTabExtended lists optional/extended functions.
-// (See http://sqlite.org/c3ref/vtab.html)
-type VTabExtended interface {
-	VTab
-	Update( /*int argc, sqlite3_value **argv, */ rowid int64) error
+// VTabUpdater is implemented by writable virtual tables.
+// Conn.VTabOnConflict can be used to know the ON CONFLICT mode in effect.
+// Errno (like ErrConstraint) can be returned to specify the result code.
+// (See http://sqlite.org/vtab.html#xupdate)
+type VTabUpdater interface {
+	// Delete removes the row identified by rowid.
+	Delete(rowid int64) error
+	// Insert adds a new row with the specified column values.
+	// rowid is nil when no rowid is specified by the INSERT statement.
+	// The rowid of the inserted row must be returned.
+	Insert(rowid *int64, values []interface{}) (int64, error)
+	// Update modifies the row identified by oldRowid.
+	// newRowid is different from oldRowid when the rowid is updated.
+	Update(oldRowid, newRowid int64, values []interface{}) error
+}
 
-	Begin() error
-	Sync() error
-	Commit() error
-	Rollback() error
+// VTabValueUpdater is implemented by writable virtual tables wanting the raw xUpdate arguments
+// (for example, to check Value.NoChange).
+// It takes precedence over VTabUpdater.
+// args[0] is the old rowid (NULL for INSERT), args[1] is the new rowid (absent for DELETE)
+// and args[2:] are the column values.
+// The rowid of the inserted row must be returned (ignored for DELETE and UPDATE).
+// (See http://sqlite.org/vtab.html#xupdate)
+type VTabValueUpdater interface {
+	UpdateValues(args []*Value) (int64, error)
+}
 
-	//FindFunction(nArg int, name string /*, void (**pxFunc)(sqlite3_context*,int,sqlite3_value**), void **ppArg*/) error
-	Rename(newName string) error
+// VTabTransaction is implemented by virtual tables taking part in transactions.
+// Begin is only called for virtual tables being modified.
+// (See http://sqlite.org/vtab.html#xBegin)
+type VTabTransaction interface {
+	Begin() error    // See http://sqlite.org/vtab.html#xBegin
+	Sync() error     // See http://sqlite.org/vtab.html#xsync
+	Commit() error   // See http://sqlite.org/vtab.html#xcommit
+	Rollback() error // See http://sqlite.org/vtab.html#xrollback
+}
 
+// VTabSavepointer is implemented by virtual tables supporting nested transactions.
+// i is the savepoint level.
+// (See http://sqlite.org/vtab.html#xsavepoint)
+type VTabSavepointer interface {
 	Savepoint(i int) error
 	Release(i int) error
 	RollbackTo(i int) error
 }
 
+// VTabFunctionFinder is implemented by virtual tables overloading functions.
+// FindFunction is called when a function (like MATCH, LIKE, GLOB or any custom function)
+// has a column of the virtual table as its first argument
+// (or as its left operand for the infix operators: 'col MATCH ?' is 'match(?, col)').
+// It must return nil when the function is not overloaded.
+// While the virtual table is connected, the same function must be returned for a given name and nArg:
+// the first one returned is reused without calling FindFunction again.
+// (See http://sqlite.org/vtab.html#xfindfunction)
+type VTabFunctionFinder interface {
+	FindFunction(nArg int, name string) ScalarFunction
+}
+
+// VTabRenamer is implemented by virtual tables that need to be notified when they are renamed
+// (for example, to rename their shadow tables).
+// (See http://sqlite.org/vtab.html#xrename)
+type VTabRenamer interface {
+	Rename(newName string) error
+}
+
+// VTabIntegrityChecker is implemented by virtual tables checking their content
+// on PRAGMA integrity_check and quick_check (SQLite >= 3.44).
+// A non-empty problem description must be returned when a problem is found,
+// err is reserved for failures of the check itself.
+// (See http://sqlite.org/vtab.html#xintegrity)
+type VTabIntegrityChecker interface {
+	Integrity(schema, table string, quick bool) (problem string, err error)
+}
+
+// VTabExtended lists optional/extended functions.
+// (See http://sqlite.org/c3ref/vtab.html)
+type VTabExtended interface {
+	VTab
+	VTabUpdater
+	VTabTransaction
+
+	VTabFunctionFinder
+	VTabRenamer
+
+	VTabSavepointer
+}
+
 // VTabCursor describes cursors that point into the virtual table and are used to loop through the virtual table.
 // (See http://sqlite.org/c3ref/vtab_cursor.html)
 type VTabCursor interface {
-	Close() error                                                                 // See http://sqlite.org/vtab.html#xclose
-	Filter( /*idxNum int, idxStr string, int argc, sqlite3_value **argv*/ ) error // See http://sqlite.org/vtab.html#xfilter
-	Next() error                                                                  // See http://sqlite.org/vtab.html#xnext
-	EOF() bool                                                                    // See http://sqlite.org/vtab.html#xeof
+	Close() error // See http://sqlite.org/vtab.html#xclose
+	// idxNum and idxStr are the values chosen by VTab.BestIndex.
+	// args contains the values of the constraints with IndexConstraintUsage.ArgvIndex > 0 (in ArgvIndex order).
+	Filter(idxNum int, idxStr string, args []interface{}) error // See http://sqlite.org/vtab.html#xfilter
+	Next() error                                                // See http://sqlite.org/vtab.html#xnext
+	EOF() bool                                                  // See http://sqlite.org/vtab.html#xeof
 	// col is zero-based so the first column is numbered 0
 	Column(c *Context, col int) error // See http://sqlite.org/vtab.html#xcolumn
 	Rowid() (int64, error)            // See http://sqlite.org/vtab.html#xrowid
 }
 
+// VTabCursorValueFilter is implemented by cursors wanting the constraint values unconverted.
+// FilterValues is called instead of VTabCursor.Filter.
+// args are only valid during the call.
+type VTabCursorValueFilter interface {
+	FilterValues(idxNum int, idxStr string, args []*Value) error
+}
+
+// OnConflict enumerates conflict resolution modes
+type OnConflict int32
+
+// Conflict resolution modes
+const (
+	OnConflictRollback OnConflict = C.SQLITE_ROLLBACK
+	OnConflictIgnore   OnConflict = C.SQLITE_IGNORE
+	OnConflictFail     OnConflict = C.SQLITE_FAIL
+	OnConflictAbort    OnConflict = C.SQLITE_ABORT
+	OnConflictReplace  OnConflict = C.SQLITE_REPLACE
+)
+
+// VTabOnConflict returns the ON CONFLICT mode in effect for the current statement.
+// Must only be called from within VTabUpdater methods.
+// (See http://sqlite.org/c3ref/vtab_on_conflict.html)
+func (c *Conn) VTabOnConflict() OnConflict {
+	return OnConflict(C.sqlite3_vtab_on_conflict(c.db))
+}
+
 // DeclareVTab declares the Schema of a virtual table.
 // (See http://sqlite.org/c3ref/declare_vtab.html)
 func (c *Conn) DeclareVTab(sql string) error {
@@ -236,15 +763,41 @@ func (c *Conn) DeclareVTab(sql string) error {
 // Cannot be used with Go >= 1.6 and cgocheck enabled.
 // (See http://sqlite.org/c3ref/create_module.html)
 func (c *Conn) CreateModule(moduleName string, module Module) error {
+	return c.createModule(moduleName, module, false)
+}
+
+// CreateEponymousModule registers an eponymous-only virtual table implementation.
+// The module cannot be used with CREATE VIRTUAL TABLE but can be used directly in FROM clauses
+// or as a table-valued function:
+//
+//	SELECT * FROM moduleName(arg1, arg2)
+//
+// Cannot be used with Go >= 1.6 and cgocheck enabled.
+// (See http://sqlite.org/vtab.html#eponymous_only_virtual_tables)
+func (c *Conn) CreateEponymousModule(moduleName string, module EponymousModule) error {
+	return c.createModule(moduleName, module, true)
+}
+
+func (c *Conn) createModule(moduleName string, module EponymousModule, eponymousOnly bool) error {
 	mname := C.CString(moduleName)
 	defer C.free(unsafe.Pointer(mname))
 	// To make sure it is not gced, keep a reference in the connection.
-	udm := &sqliteModule{c, moduleName, module, nil}
+	udm := &sqliteModule{c, moduleName, module, nil, -1}
+	if sn, ok := module.(ShadowNamer); ok && VersionNumber() >= 3026000 {
+		if udm.shadow = acquireShadowNamer(moduleName, sn); udm.shadow < 0 {
+			return c.specificError("cannot register module %q: more than %d distinct modules implementing ShadowNamer",
+				moduleName, maxShadowNamers)
+		}
+	}
 	if len(c.modules) == 0 {
 		c.modules = make(map[string]*sqliteModule)
 	}
 	c.modules[moduleName] = udm // FIXME What happens if different modules are registered with the same name?
-	return c.error(C.goSqlite3CreateModule(c.db, mname, unsafe.Pointer(udm)),
+	if eponymousOnly {
+		return c.error(C.goSqlite3CreateModule(c.db, mname, unsafe.Pointer(udm), 1, C.int(udm.shadow)),
+			fmt.Sprintf("Conn.CreateEponymousModule(%q)", moduleName))
+	}
+	return c.error(C.goSqlite3CreateModule(c.db, mname, unsafe.Pointer(udm), 0, C.int(udm.shadow)),
 		fmt.Sprintf("Conn.CreateModule(%q)", moduleName))
 }
 
@@ -254,38 +807,42 @@ CreateModule(                       int sqlite3_create_module_v2(
  |- c *Conn                          |- sqlite3 *db
  |- moduleName string                |- const char *zName
  |- goModule                         |- const sqlite3_module *p (~) Methods for the module
+ |                                   |  (goEponymousOnlyModule for CreateEponymousModule)
  |- *sqliteModule                    |- void *pClientData () Client data for xCreate/xConnect
  \- goVDestroy                       \- void(*xDestroy)(void*) () Client data destructor function
 )                                   )
 
 goModule                            sqlite3_module {
                                      |- int iVersion
-goMInit                              |- int (*xCreate)(sqlite3*, void *pAux, int argc, char **argv, sqlite3_vtab **ppVTab,
+goMInit (nil if eponymous only)      |- int (*xCreate)(sqlite3*, void *pAux, int argc, char **argv, sqlite3_vtab **ppVTab,
                                              char **pzErr)
 goMInit                              |- int (*xConnect)(sqlite3*, void *pAux, int argc, char **argv, sqlite3_vtab **ppVTab,
                                              char **pzErr)
-x                                    |- int (*xBestIndex)(sqlite3_vtab *pVTab, sqlite3_index_info*)
+goVBestIndex                         |- int (*xBestIndex)(sqlite3_vtab *pVTab, sqlite3_index_info*)
 goVRelease                           |- int (*xDisconnect)(sqlite3_vtab *pVTab)
 goVRelease                           |- int (*xDestroy)(sqlite3_vtab *pVTab)
 goVOpen                              |- int (*xOpen)(sqlite3_vtab *pVTab, sqlite3_vtab_cursor **ppCursor)
 goVClose                             |- int (*xClose)(sqlite3_vtab_cursor*)
-x                                    |- int (*xFilter)(sqlite3_vtab_cursor*, int idxNum, const char *idxStr, int argc,
+goVFilter                            |- int (*xFilter)(sqlite3_vtab_cursor*, int idxNum, const char *idxStr, int argc,
                                              sqlite3_value **argv)
-x                                    |- int (*xNext)(sqlite3_vtab_cursor*)
-x                                    |- int (*xEof)(sqlite3_vtab_cursor*)
-x                                    |- int (*xColumn)(sqlite3_vtab_cursor*, sqlite3_context*, int)
-x                                    |- int (*xRowid)(sqlite3_vtab_cursor*, sqlite_int64 *pRowid)
-o                                    |- int (*xUpdate)(sqlite3_vtab *, int, sqlite3_value **, sqlite_int64 *)
-o                                    |- int (*xBegin)(sqlite3_vtab *pVTab)
-o                                    |- int (*xSync)(sqlite3_vtab *pVTab)
-o                                    |- int (*xCommit)(sqlite3_vtab *pVTab)
-o                                    |- int (*xRollback)(sqlite3_vtab *pVTab)
-o                                    |- int (*xFindFunction)(sqlite3_vtab *pVtab, int nArg, const char *zName,
+goVNext                              |- int (*xNext)(sqlite3_vtab_cursor*)
+goVEof                               |- int (*xEof)(sqlite3_vtab_cursor*)
+goVColumn                            |- int (*xColumn)(sqlite3_vtab_cursor*, sqlite3_context*, int)
+goVRowid                             |- int (*xRowid)(sqlite3_vtab_cursor*, sqlite_int64 *pRowid)
+goVUpdate                            |- int (*xUpdate)(sqlite3_vtab *, int, sqlite3_value **, sqlite_int64 *)
+goVBegin                             |- int (*xBegin)(sqlite3_vtab *pVTab)
+goVSync                              |- int (*xSync)(sqlite3_vtab *pVTab)
+goVCommit                            |- int (*xCommit)(sqlite3_vtab *pVTab)
+goVRollback                          |- int (*xRollback)(sqlite3_vtab *pVTab)
+goVFindFunction                      |- int (*xFindFunction)(sqlite3_vtab *pVtab, int nArg, const char *zName,
                                              void (**pxFunc)(sqlite3_context*,int,sqlite3_value**), void **ppArg)
-x                                    |- int (*xRename)(sqlite3_vtab *pVtab, const char *zNew)
-o                                    |- int (*xSavepoint)(sqlite3_vtab *pVTab, int)
-o                                    |- int (*xRelease)(sqlite3_vtab *pVTab, int)
-o                                    \- int (*xRollbackTo)(sqlite3_vtab *pVTab, int)
+goVRename                            |- int (*xRename)(sqlite3_vtab *pVtab, const char *zNew)
+goVSavepoint                         |- int (*xSavepoint)(sqlite3_vtab *pVTab, int)
+goVReleaseSavepoint                  |- int (*xRelease)(sqlite3_vtab *pVTab, int)
+goVRollbackTo                        |- int (*xRollbackTo)(sqlite3_vtab *pVTab, int)
+goMShadowName                        |- int (*xShadowName)(const char*) (per ShadowNamer module)
+goVIntegrity                         \- int (*xIntegrity)(sqlite3_vtab *pVTab, const char *zSchema, const char *zTabName,
+                                             int mFlags, char **pzErr)
                                     }
 
 DeclareVTab                         int sqlite3_declare_vtab( (Called in xCreate/xConnect)
@@ -5,7 +5,11 @@
 package sqlite_test
 
 import (
+	"errors"
 	"fmt"
+	"runtime"
+	"sort"
+	"strings"
 	"testing"
 
 	"github.com/bmizerany/assert"
@@ -13,17 +17,20 @@ import (
 )
 
 type testModule struct {
-	t        *testing.T
-	intarray []int
+	t         *testing.T
+	intarray  []int
+	bestIndex func(info *IndexInfo) error
 }
 
 type testVTab struct {
-	intarray []int
+	intarray  []int
+	bestIndex func(info *IndexInfo) error
 }
 
 type testVTabCursor struct {
 	vTab  *testVTab
 	index int /* Current cursor position */
+	end   int
 }
 
 func (m testModule) Create(c *Conn, args []string) (VTab, error) {
@@ -39,7 +46,7 @@ func (m testModule) Create(c *Conn, args []string) (VTab, error) {
 	if err != nil {
 		return nil, err
 	}
-	return &testVTab{m.intarray}, nil
+	return &testVTab{m.intarray, m.bestIndex}, nil
 }
 func (m testModule) Connect(c *Conn, args []string) (VTab, error) {
 	//println("testVTab.Connect")
@@ -50,10 +57,26 @@ func (m testModule) DestroyModule() {
 	//println("testModule.DestroyModule")
 }
 
-func (v *testVTab) BestIndex() error {
+func (v *testVTab) BestIndex(info *IndexInfo) error {
 	//fmt.Printf("testVTab.BestIndex: %v\n", v)
+	if v.bestIndex != nil {
+		return v.bestIndex(info)
+	}
 	return nil
 }
+// findFunctionCalls counts testVTab.FindFunction calls.
+var findFunctionCalls int
+
+func (v *testVTab) FindFunction(nArg int, name string) ScalarFunction {
+	//fmt.Printf("testVTab.FindFunction(%d, %s): %v\n", nArg, name, v)
+	findFunctionCalls++
+	if nArg != 2 || strings.ToLower(name) != "match" {
+		return nil
+	}
+	return func(ctx *ScalarContext, nArg int) {
+		ctx.ResultBool(ctx.Int(0) == ctx.Int(1))
+	}
+}
 func (v *testVTab) Disconnect() error {
 	//fmt.Printf("testVTab.Disconnect: %v\n", v)
 	return nil
@@ -64,16 +87,28 @@ func (v *testVTab) Destroy() error {
 }
 func (v *testVTab) Open() (VTabCursor, error) {
 	//fmt.Printf("testVTab.Open: %v\n", v)
-	return &testVTabCursor{v, 0}, nil
+	return &testVTabCursor{v, 0, 0}, nil
 }
 
 func (vc *testVTabCursor) Close() error {
 	//fmt.Printf("testVTabCursor.Close: %v\n", vc)
 	return nil
 }
-func (vc *testVTabCursor) Filter( /*idxNum int, idxStr string, int argc, sqlite3_value **argv*/ ) error {
+func (vc *testVTabCursor) Filter(idxNum int, idxStr string, args []interface{}) error {
 	//fmt.Printf("testVTabCursor.Filter: %v\n", vc)
 	vc.index = 0
+	vc.end = len(vc.vTab.intarray)
+	if idxNum == 1 { // rowid = ?
+		if len(args) != 1 {
+			return fmt.Errorf("one argument expected for %q", idxStr)
+		}
+		rowid, ok := args[0].(int64)
+		if !ok || rowid < 0 || rowid >= int64(vc.end) {
+			vc.index = vc.end
+		} else {
+			vc.index, vc.end = int(rowid), int(rowid)+1
+		}
+	}
 	return nil
 }
 func (vc *testVTabCursor) Next() error {
@@ -83,7 +118,7 @@ func (vc *testVTabCursor) Next() error {
 }
 func (vc *testVTabCursor) EOF() bool {
 	//fmt.Printf("testVTabCursor.EOF: %v\n", vc)
-	return vc.index >= len(vc.vTab.intarray)
+	return vc.index >= vc.end
 }
 func (vc *testVTabCursor) Column(c *Context, col int) error {
 	//fmt.Printf("testVTabCursor.Column(%d): %v\n", col, vc)
@@ -104,7 +139,7 @@ func TestCreateModule(t *testing.T) {
 	db := open(t)
 	defer checkClose(db, t)
 	intarray := []int{1, 2, 3}
-	err := db.CreateModule("test", testModule{t, intarray})
+	err := db.CreateModule("test", testModule{t, intarray, nil})
 	checkNoError(t, err, "couldn't create module: %s")
 	err = db.Exec("CREATE VIRTUAL TABLE vtab USING test('1', 2, three)")
 	checkNoError(t, err, "couldn't create virtual table: %s")
@@ -125,3 +160,762 @@ func TestCreateModule(t *testing.T) {
 	err = db.Exec("DROP TABLE vtab")
 	checkNoError(t, err, "couldn't drop virtual table: %s")
 }
+
+func TestBestIndex(t *testing.T) {
+	skipIfCgoCheckActive(t)
+
+	db := open(t)
+	defer checkClose(db, t)
+	intarray := []int{1, 2, 3}
+	var infos []IndexInfo
+	err := db.CreateModule("test", testModule{t, intarray, func(info *IndexInfo) error {
+		infos = append(infos, *info)
+		for i, c := range info.Constraints {
+			if c.Column == 0 && c.Op == OpEq && c.Usable {
+				return errors.New("no plan")
+			}
+			if c.Column == -1 && c.Op == OpEq && c.Usable {
+				info.IdxNum = 1
+				info.IdxStr = "rowid"
+				info.EstimatedCost = 1
+				info.EstimatedRows = 1
+				info.IdxFlags = IndexScanUnique
+				info.ConstraintUsages[i].ArgvIndex = 1
+			}
+		}
+		if len(info.OrderBys) == 1 && info.OrderBys[0].Column == -1 && !info.OrderBys[0].Desc {
+			info.OrderByConsumed = true
+		}
+		return nil
+	}})
+	checkNoError(t, err, "couldn't create module: %s")
+	err = db.Exec("CREATE VIRTUAL TABLE vtab USING test('1', 2, three)")
+	checkNoError(t, err, "couldn't create virtual table: %s")
+
+	s, err := db.Prepare("SELECT test FROM vtab WHERE rowid = ? ORDER BY rowid", 1)
+	checkNoError(t, err, "couldn't select from virtual table: %s")
+	defer checkFinalize(s, t)
+	assert.T(t, len(infos) > 0, "BestIndex not called")
+	var values []int
+	err = s.Select(func(s *Stmt) error {
+		var value int
+		if err := s.Scan(&value); err != nil {
+			return err
+		}
+		values = append(values, value)
+		return nil
+	})
+	checkNoError(t, err, "couldn't select from virtual table: %s")
+	assert.Equal(t, []int{2}, values)
+	err = s.Select(func(s *Stmt) error {
+		return errors.New("no row expected")
+	}, 5)
+	checkNoError(t, err, "couldn't select from virtual table: %s")
+	var found bool
+	for _, info := range infos {
+		if len(info.Constraints) == 1 && info.Constraints[0].Usable {
+			assert.Equal(t, -1, info.Constraints[0].Column)
+			assert.Equal(t, OpEq, info.Constraints[0].Op)
+			assert.Equal(t, 1, len(info.ConstraintUsages))
+			assert.Equal(t, 1, len(info.OrderBys))
+			assert.Equal(t, uint64(1), info.ColUsed)
+			found = true
+		}
+	}
+	assert.T(t, found, "rowid constraint expected")
+
+	_, err = db.Prepare("SELECT * FROM vtab WHERE test = 1")
+	assert.T(t, err != nil, "error expected")
+	assert.T(t, strings.Contains(err.Error(), "no plan"), err.Error())
+
+	err = db.Exec("DROP TABLE vtab")
+	checkNoError(t, err, "couldn't drop virtual table: %s")
+}
+
+type kvModule struct {
+	rows map[int64]string
+}
+
+type kvVTab struct {
+	c    *Conn
+	rows map[int64]string
+}
+
+type kvVTabCursor struct {
+	vTab  *kvVTab
+	keys  []int64
+	index int
+}
+
+func (m kvModule) Create(c *Conn, args []string) (VTab, error) {
+	err := c.DeclareVTab("CREATE TABLE x(value TEXT)")
+	if err != nil {
+		return nil, err
+	}
+	return &kvVTab{c, m.rows}, nil
+}
+func (m kvModule) Connect(c *Conn, args []string) (VTab, error) {
+	return m.Create(c, args)
+}
+func (m kvModule) DestroyModule() {
+}
+
+func (v *kvVTab) BestIndex(info *IndexInfo) error {
+	return nil
+}
+func (v *kvVTab) Disconnect() error {
+	return nil
+}
+func (v *kvVTab) Destroy() error {
+	return nil
+}
+func (v *kvVTab) Open() (VTabCursor, error) {
+	return &kvVTabCursor{vTab: v}, nil
+}
+func (v *kvVTab) Delete(rowid int64) error {
+	delete(v.rows, rowid)
+	return nil
+}
+func (v *kvVTab) Insert(rowid *int64, values []interface{}) (int64, error) {
+	var r int64
+	if rowid == nil {
+		for k := range v.rows {
+			if k > r {
+				r = k
+			}
+		}
+		r++
+	} else {
+		r = *rowid
+		if _, ok := v.rows[r]; ok && v.c.VTabOnConflict() != OnConflictReplace {
+			return 0, ErrConstraint
+		}
+	}
+	v.rows[r], _ = values[0].(string)
+	return r, nil
+}
+func (v *kvVTab) Update(oldRowid, newRowid int64, values []interface{}) error {
+	delete(v.rows, oldRowid)
+	v.rows[newRowid], _ = values[0].(string)
+	return nil
+}
+
+func (vc *kvVTabCursor) Close() error {
+	return nil
+}
+func (vc *kvVTabCursor) Filter(idxNum int, idxStr string, args []interface{}) error {
+	vc.keys = vc.keys[:0]
+	for k := range vc.vTab.rows {
+		vc.keys = append(vc.keys, k)
+	}
+	sort.Slice(vc.keys, func(i, j int) bool { return vc.keys[i] < vc.keys[j] })
+	vc.index = 0
+	return nil
+}
+func (vc *kvVTabCursor) Next() error {
+	vc.index++
+	return nil
+}
+func (vc *kvVTabCursor) EOF() bool {
+	return vc.index >= len(vc.keys)
+}
+func (vc *kvVTabCursor) Column(c *Context, col int) error {
+	c.ResultText(vc.vTab.rows[vc.keys[vc.index]])
+	return nil
+}
+func (vc *kvVTabCursor) Rowid() (int64, error) {
+	return vc.keys[vc.index], nil
+}
+
+func TestUpdateModule(t *testing.T) {
+	skipIfCgoCheckActive(t)
+
+	db := open(t)
+	defer checkClose(db, t)
+	rows := make(map[int64]string)
+	err := db.CreateModule("kv", kvModule{rows})
+	checkNoError(t, err, "couldn't create module: %s")
+	err = db.Exec("CREATE VIRTUAL TABLE vtab USING kv()")
+	checkNoError(t, err, "couldn't create virtual table: %s")
+
+	rowid, err := db.Insert("INSERT INTO vtab VALUES ('a')")
+	checkNoError(t, err, "couldn't insert into virtual table: %s")
+	assert.Equal(t, int64(1), rowid)
+	rowid, err = db.Insert("INSERT INTO vtab (rowid, value) VALUES (10, 'b')")
+	checkNoError(t, err, "couldn't insert into virtual table: %s")
+	assert.Equal(t, int64(10), rowid)
+	assert.Equal(t, map[int64]string{1: "a", 10: "b"}, rows)
+
+	err = db.Exec("UPDATE vtab SET value = 'c' WHERE rowid = 10")
+	checkNoError(t, err, "couldn't update virtual table: %s")
+	err = db.Exec("UPDATE vtab SET rowid = 11 WHERE rowid = 10")
+	checkNoError(t, err, "couldn't update virtual table: %s")
+	err = db.Exec("DELETE FROM vtab WHERE value = 'a'")
+	checkNoError(t, err, "couldn't delete from virtual table: %s")
+	assert.Equal(t, map[int64]string{11: "c"}, rows)
+
+	err = db.Exec("INSERT INTO vtab (rowid, value) VALUES (11, 'd')")
+	assert.T(t, err != nil, "constraint error expected")
+	if se, ok := err.(StmtError); !ok || se.Code() != ErrConstraint {
+		t.Errorf("got %#v; want constraint error", err)
+	}
+	err = db.Exec("INSERT OR REPLACE INTO vtab (rowid, value) VALUES (11, 'd')")
+	checkNoError(t, err, "couldn't replace into virtual table: %s")
+	var value string
+	err = db.OneValue("SELECT value FROM vtab WHERE rowid = 11", &value)
+	checkNoError(t, err, "couldn't select from virtual table: %s")
+	assert.Equal(t, "d", value)
+
+	err = db.Exec("INSERT INTO vtab (rowid, value) VALUES ('x', 'e')")
+	assert.T(t, err != nil, "datatype mismatch error expected")
+	if se, ok := err.(StmtError); !ok || se.Code() != ErrMismatch {
+		t.Errorf("got %#v; want datatype mismatch error", err)
+	}
+
+	err = db.Exec("DROP TABLE vtab")
+	checkNoError(t, err, "couldn't drop virtual table: %s")
+
+	err = db.CreateModule("test", testModule{t, []int{1}, nil})
+	checkNoError(t, err, "couldn't create module: %s")
+	err = db.Exec("CREATE VIRTUAL TABLE vtab USING test('1', 2, three)")
+	checkNoError(t, err, "couldn't create virtual table: %s")
+	err = db.Exec("INSERT INTO vtab VALUES ('a')")
+	assert.T(t, err != nil, "read-only error expected")
+}
+
+type rawModule struct {
+	kvModule
+	noChanges *[]bool
+}
+
+type rawVTab struct {
+	*kvVTab
+	noChanges *[]bool
+}
+
+type rawVTabCursor struct {
+	*kvVTabCursor
+	filterTypes []Type
+}
+
+func (m rawModule) Create(c *Conn, args []string) (VTab, error) {
+	err := c.DeclareVTab("CREATE TABLE x(value TEXT, computed INT)")
+	if err != nil {
+		return nil, err
+	}
+	return &rawVTab{&kvVTab{c, m.rows}, m.noChanges}, nil
+}
+func (m rawModule) Connect(c *Conn, args []string) (VTab, error) {
+	return m.Create(c, args)
+}
+
+func (v *rawVTab) BestIndex(info *IndexInfo) error {
+	for i, c := range info.Constraints {
+		if c.Usable && c.Op == OpEq && c.Column == 0 {
+			info.ConstraintUsages[i].ArgvIndex = 1
+			break
+		}
+	}
+	return nil
+}
+func (v *rawVTab) Open() (VTabCursor, error) {
+	return &rawVTabCursor{kvVTabCursor: &kvVTabCursor{vTab: v.kvVTab}}, nil
+}
+func (v *rawVTab) UpdateValues(args []*Value) (int64, error) {
+	if len(args) == 1 {
+		return 0, v.Delete(args[0].Int64())
+	} else if args[0].Type() == Null {
+		return v.Insert(nil, []interface{}{args[2].Interface()})
+	}
+	*v.noChanges = append(*v.noChanges, args[2].NoChange(), args[3].NoChange())
+	return 0, v.Update(args[0].Int64(), args[1].Int64(), []interface{}{args[2].Text()})
+}
+
+func (vc *rawVTabCursor) FilterValues(idxNum int, idxStr string, args []*Value) error {
+	for _, arg := range args {
+		vc.filterTypes = append(vc.filterTypes, arg.Type())
+	}
+	return vc.Filter(idxNum, idxStr, nil)
+}
+func (vc *rawVTabCursor) Column(c *Context, col int) error {
+	if col == 0 {
+		return vc.kvVTabCursor.Column(c, col)
+	}
+	if c.VTabNoChange() {
+		return nil
+	}
+	c.ResultInt64(int64(len(vc.vTab.rows[vc.keys[vc.index]])))
+	return nil
+}
+
+func TestRawValuesModule(t *testing.T) {
+	skipIfCgoCheckActive(t)
+
+	db := open(t)
+	defer checkClose(db, t)
+	rows := make(map[int64]string)
+	var noChanges []bool
+	err := db.CreateModule("raw", rawModule{kvModule{rows}, &noChanges})
+	checkNoError(t, err, "couldn't create module: %s")
+	err = db.Exec("CREATE VIRTUAL TABLE vtab USING raw()")
+	checkNoError(t, err, "couldn't create virtual table: %s")
+
+	err = db.Exec("INSERT INTO vtab (value) VALUES ('a'), ('bb')")
+	checkNoError(t, err, "couldn't insert into virtual table: %s")
+	var n int
+	err = db.OneValue("SELECT computed FROM vtab WHERE value = ?", &n, "bb")
+	checkNoError(t, err, "couldn't select from virtual table: %s")
+	assert.Equal(t, 2, n)
+
+	err = db.Exec("UPDATE vtab SET value = 'ccc' WHERE rowid = 1")
+	checkNoError(t, err, "couldn't update virtual table: %s")
+	assert.Equal(t, map[int64]string{1: "ccc", 2: "bb"}, rows)
+	assert.Equal(t, []bool{false, true}, noChanges)
+
+	err = db.Exec("DELETE FROM vtab WHERE rowid = 2")
+	checkNoError(t, err, "couldn't delete from virtual table: %s")
+	assert.Equal(t, map[int64]string{1: "ccc"}, rows)
+
+	err = db.Exec("DROP TABLE vtab")
+	checkNoError(t, err, "couldn't drop virtual table: %s")
+}
+
+type txModule struct {
+	kvModule
+	calls *[]string
+}
+
+type txVTab struct {
+	*kvVTab
+	calls      *[]string
+	savepoints map[int]map[int64]string
+}
+
+func (m txModule) Create(c *Conn, args []string) (VTab, error) {
+	v, err := m.kvModule.Create(c, args)
+	if err != nil {
+		return nil, err
+	}
+	return &txVTab{kvVTab: v.(*kvVTab), calls: m.calls}, nil
+}
+func (m txModule) Connect(c *Conn, args []string) (VTab, error) {
+	return m.Create(c, args)
+}
+
+func (v *txVTab) snapshot() map[int64]string {
+	rows := make(map[int64]string, len(v.rows))
+	for k, r := range v.rows {
+		rows[k] = r
+	}
+	return rows
+}
+func (v *txVTab) restore(rows map[int64]string) {
+	for k := range v.rows {
+		delete(v.rows, k)
+	}
+	for k, r := range rows {
+		v.rows[k] = r
+	}
+}
+
+func (v *txVTab) Begin() error {
+	*v.calls = append(*v.calls, "Begin")
+	v.savepoints = map[int]map[int64]string{-1: v.snapshot()}
+	return nil
+}
+func (v *txVTab) Sync() error {
+	*v.calls = append(*v.calls, "Sync")
+	return nil
+}
+func (v *txVTab) Commit() error {
+	*v.calls = append(*v.calls, "Commit")
+	v.savepoints = nil
+	return nil
+}
+func (v *txVTab) Rollback() error {
+	*v.calls = append(*v.calls, "Rollback")
+	v.restore(v.savepoints[-1])
+	v.savepoints = nil
+	return nil
+}
+func (v *txVTab) Savepoint(i int) error {
+	*v.calls = append(*v.calls, fmt.Sprintf("Savepoint(%d)", i))
+	v.savepoints[i] = v.snapshot()
+	return nil
+}
+func (v *txVTab) Release(i int) error {
+	*v.calls = append(*v.calls, fmt.Sprintf("Release(%d)", i))
+	for l := range v.savepoints {
+		if l >= i {
+			delete(v.savepoints, l)
+		}
+	}
+	return nil
+}
+func (v *txVTab) RollbackTo(i int) error {
+	*v.calls = append(*v.calls, fmt.Sprintf("RollbackTo(%d)", i))
+	v.restore(v.savepoints[i])
+	return nil
+}
+
+func TestTransactionModule(t *testing.T) {
+	skipIfCgoCheckActive(t)
+
+	db := open(t)
+	defer checkClose(db, t)
+	rows := make(map[int64]string)
+	var calls []string
+	err := db.CreateModule("tx", txModule{kvModule{rows}, &calls})
+	checkNoError(t, err, "couldn't create module: %s")
+	err = db.Exec("CREATE VIRTUAL TABLE vtab USING tx()")
+	checkNoError(t, err, "couldn't create virtual table: %s")
+	calls = calls[:0]
+
+	err = db.Exec(`BEGIN;
+	INSERT INTO vtab VALUES ('a');
+	SAVEPOINT s1;
+	INSERT INTO vtab VALUES ('b');
+	ROLLBACK TO s1;
+	RELEASE s1;
+	COMMIT`)
+	checkNoError(t, err, "couldn't modify virtual table: %s")
+	assert.Equal(t, map[int64]string{1: "a"}, rows)
+	assert.Equal(t, []string{"Begin", "Savepoint(0)", "RollbackTo(0)", "Release(0)", "Sync", "Commit"}, calls)
+
+	calls = calls[:0]
+	err = db.Exec(`BEGIN;
+	INSERT INTO vtab VALUES ('c');
+	ROLLBACK`)
+	checkNoError(t, err, "couldn't modify virtual table: %s")
+	assert.Equal(t, map[int64]string{1: "a"}, rows)
+	assert.Equal(t, []string{"Begin", "Rollback"}, calls)
+}
+
+type seriesModule struct {
+}
+
+type seriesVTab struct {
+}
+
+type seriesVTabCursor struct {
+	value, stop int64
+}
+
+func (m seriesModule) Connect(c *Conn, args []string) (VTab, error) {
+	err := c.DeclareVTab("CREATE TABLE x(value INTEGER, start HIDDEN, stop HIDDEN)")
+	if err != nil {
+		return nil, err
+	}
+	return &seriesVTab{}, nil
+}
+func (m seriesModule) DestroyModule() {
+}
+
+func (v *seriesVTab) BestIndex(info *IndexInfo) error {
+	var unusable bool
+	for i, c := range info.Constraints {
+		if c.Op != OpEq || c.Column < 1 {
+			continue
+		}
+		if !c.Usable {
+			unusable = true
+			continue
+		}
+		info.IdxNum |= c.Column
+		info.ConstraintUsages[i].ArgvIndex = c.Column
+		info.ConstraintUsages[i].Omit = true
+	}
+	if info.IdxNum != 3 {
+		if unusable {
+			return ErrConstraint
+		}
+		return errors.New("start and stop arguments expected")
+	}
+	return nil
+}
+func (v *seriesVTab) Disconnect() error {
+	return nil
+}
+func (v *seriesVTab) Destroy() error {
+	return nil
+}
+func (v *seriesVTab) Open() (VTabCursor, error) {
+	return &seriesVTabCursor{}, nil
+}
+
+func (vc *seriesVTabCursor) Close() error {
+	return nil
+}
+func (vc *seriesVTabCursor) Filter(idxNum int, idxStr string, args []interface{}) error {
+	vc.value, _ = args[0].(int64)
+	vc.stop, _ = args[1].(int64)
+	return nil
+}
+func (vc *seriesVTabCursor) Next() error {
+	vc.value++
+	return nil
+}
+func (vc *seriesVTabCursor) EOF() bool {
+	return vc.value > vc.stop
+}
+func (vc *seriesVTabCursor) Column(c *Context, col int) error {
+	c.ResultInt64(vc.value)
+	return nil
+}
+func (vc *seriesVTabCursor) Rowid() (int64, error) {
+	return vc.value, nil
+}
+
+func TestEponymousModule(t *testing.T) {
+	skipIfCgoCheckActive(t)
+
+	db := open(t)
+	defer checkClose(db, t)
+	err := db.CreateEponymousModule("series", seriesModule{})
+	checkNoError(t, err, "couldn't create module: %s")
+
+	var values []int
+	err = db.Select("SELECT value FROM series(?, 5)", func(s *Stmt) error {
+		var value int
+		if err := s.Scan(&value); err != nil {
+			return err
+		}
+		values = append(values, value)
+		return nil
+	}, 2)
+	checkNoError(t, err, "couldn't select from table-valued function: %s")
+	assert.Equal(t, []int{2, 3, 4, 5}, values)
+
+	var sum int
+	err = db.OneValue("SELECT sum(value) FROM series WHERE start = 1 AND stop = 10", &sum)
+	checkNoError(t, err, "couldn't select from eponymous virtual table: %s")
+	assert.Equal(t, 55, sum)
+
+	_, err = db.Prepare("SELECT value FROM series")
+	assert.T(t, err != nil, "error expected")
+
+	err = db.Exec("CREATE VIRTUAL TABLE vtab USING series()")
+	assert.T(t, err != nil, "eponymous-only module expected")
+}
+
+func TestFindFunction(t *testing.T) {
+	skipIfCgoCheckActive(t)
+
+	db := open(t)
+	defer checkClose(db, t)
+	intarray := []int{1, 2, 3}
+	err := db.CreateModule("test", testModule{t, intarray, nil})
+	checkNoError(t, err, "couldn't create module: %s")
+	err = db.Exec("CREATE VIRTUAL TABLE vtab USING test('1', 2, three)")
+	checkNoError(t, err, "couldn't create virtual table: %s")
+
+	var rowid int
+	err = db.OneValue("SELECT rowid FROM vtab WHERE test MATCH ?", &rowid, 3)
+	checkNoError(t, err, "couldn't select from virtual table: %s")
+	assert.Equal(t, 2, rowid)
+
+	err = db.OneValue("SELECT rowid FROM vtab WHERE 3 MATCH test", &rowid)
+	assert.T(t, err != nil, "error expected when function is not overloaded")
+
+	// the function object must survive while statements using it are alive
+	calls := findFunctionCalls
+	s1, err := db.Prepare("SELECT rowid FROM vtab WHERE test MATCH 1")
+	checkNoError(t, err, "couldn't prepare stmt: %s")
+	defer checkFinalize(s1, t)
+	s2, err := db.Prepare("SELECT rowid FROM vtab WHERE test MATCH 2")
+	checkNoError(t, err, "couldn't prepare stmt: %s")
+	defer checkFinalize(s2, t)
+	runtime.GC()
+	for i, s := range []*Stmt{s1, s2} {
+		checkStep(t, s)
+		_, err = s.ScanByIndex(0, &rowid)
+		checkNoError(t, err, "couldn't scan rowid: %s")
+		assert.Equal(t, i, rowid)
+		checkNoError(t, s.Reset(), "couldn't reset stmt: %s")
+	}
+	assert.Equal(t, calls, findFunctionCalls, "function objects are reused without calling FindFunction again")
+
+	err = db.Exec("DROP TABLE vtab")
+	checkNoError(t, err, "couldn't drop virtual table: %s")
+}
+
+type shadowModule struct {
+}
+
+type shadowVTab struct {
+	c    *Conn
+	name string
+}
+
+type shadowVTabCursor struct {
+	values []interface{}
+	index  int
+}
+
+func (m shadowModule) Create(c *Conn, args []string) (VTab, error) {
+	err := c.FastExec(fmt.Sprintf(`CREATE TABLE "%s_data"(value)`, args[2]))
+	if err != nil {
+		return nil, err
+	}
+	return m.Connect(c, args)
+}
+func (m shadowModule) Connect(c *Conn, args []string) (VTab, error) {
+	err := c.DeclareVTab("CREATE TABLE x(value)")
+	if err != nil {
+		return nil, err
+	}
+	return &shadowVTab{c, args[2]}, nil
+}
+func (m shadowModule) DestroyModule() {
+}
+func (m shadowModule) ShadowName(suffix string) bool {
+	return suffix == "data"
+}
+
+// otherShadowModule does not recognize the "data" suffix.
+type otherShadowModule struct {
+	shadowModule
+}
+
+func (m otherShadowModule) ShadowName(suffix string) bool {
+	return suffix == "other"
+}
+
+func (v *shadowVTab) BestIndex(info *IndexInfo) error {
+	return nil
+}
+func (v *shadowVTab) Disconnect() error {
+	return nil
+}
+func (v *shadowVTab) Destroy() error {
+	return v.c.FastExec(fmt.Sprintf(`DROP TABLE "%s_data"`, v.name))
+}
+func (v *shadowVTab) Open() (VTabCursor, error) {
+	return &shadowVTabCursor{}, nil
+}
+func (v *shadowVTab) Rename(newName string) error {
+	err := v.c.FastExec(fmt.Sprintf(`ALTER TABLE "%s_data" RENAME TO "%s_data"`, v.name, newName))
+	if err != nil {
+		return err
+	}
+	v.name = newName
+	return nil
+}
+func (v *shadowVTab) Integrity(schema, table string, quick bool) (string, error) {
+	var n int
+	err := v.c.OneValue(fmt.Sprintf(`SELECT count(*) FROM "%s"."%s_data" WHERE value IS NULL`, schema, table), &n)
+	if err != nil {
+		return "", err
+	}
+	if n > 0 {
+		return fmt.Sprintf("%d NULL value(s) in %s_data", n, table), nil
+	}
+	return "", nil
+}
+
+func (vc *shadowVTabCursor) Close() error {
+	return nil
+}
+func (vc *shadowVTabCursor) Filter(idxNum int, idxStr string, args []interface{}) error {
+	return nil
+}
+func (vc *shadowVTabCursor) Next() error {
+	vc.index++
+	return nil
+}
+func (vc *shadowVTabCursor) EOF() bool {
+	return vc.index >= len(vc.values)
+}
+func (vc *shadowVTabCursor) Column(c *Context, col int) error {
+	return nil
+}
+func (vc *shadowVTabCursor) Rowid() (int64, error) {
+	return int64(vc.index), nil
+}
+
+func TestShadowTables(t *testing.T) {
+	skipIfCgoCheckActive(t)
+
+	db := open(t)
+	defer checkClose(db, t)
+	err := db.CreateModule("shadow", shadowModule{})
+	checkNoError(t, err, "couldn't create module: %s")
+	err = db.Exec("CREATE VIRTUAL TABLE vtab USING shadow()")
+	checkNoError(t, err, "couldn't create virtual table: %s")
+	err = db.Exec("INSERT INTO vtab_data VALUES (1), (2)")
+	checkNoError(t, err, "couldn't insert into shadow table: %s")
+
+	if VersionNumber() >= 3037000 {
+		var typ string
+		err = db.OneValue("SELECT type FROM pragma_table_list WHERE name = 'vtab_data'", &typ)
+		checkNoError(t, err, "couldn't list tables: %s")
+		assert.Equal(t, "shadow", typ)
+
+		// the shadow suffixes of one module do not apply to the others
+		err = db.CreateModule("other", otherShadowModule{})
+		checkNoError(t, err, "couldn't create module: %s")
+		err = db.Exec("CREATE VIRTUAL TABLE other USING other()")
+		checkNoError(t, err, "couldn't create virtual table: %s")
+		err = db.OneValue("SELECT type FROM pragma_table_list WHERE name = 'other_data'", &typ)
+		checkNoError(t, err, "couldn't list tables: %s")
+		assert.Equal(t, "table", typ)
+	}
+
+	err = db.Exec("ALTER TABLE vtab RENAME TO renamed")
+	checkNoError(t, err, "couldn't rename virtual table: %s")
+	var n int
+	err = db.OneValue("SELECT count(*) FROM renamed_data", &n)
+	checkNoError(t, err, "couldn't select from renamed shadow table: %s")
+	assert.Equal(t, 2, n)
+
+	err = db.IntegrityCheck("main", 1, false)
+	checkNoError(t, err, "integrity check failed: %s")
+	if VersionNumber() >= 3044000 {
+		err = db.Exec("INSERT INTO renamed_data VALUES (NULL)")
+		checkNoError(t, err, "couldn't insert into shadow table: %s")
+		err = db.IntegrityCheck("main", 1, false)
+		assert.T(t, err != nil, "integrity check error expected")
+		assert.T(t, strings.Contains(err.Error(), "1 NULL value(s) in renamed_data"), err.Error())
+	}
+
+	err = db.Exec("DROP TABLE renamed")
+	checkNoError(t, err, "couldn't drop virtual table: %s")
+}
+
+func TestShadowNamerSlots(t *testing.T) {
+	skipIfCgoCheckActive(t)
+	if VersionNumber() < 3026000 {
+		t.Skip("xShadowName requires SQLite 3.26")
+	}
+
+	// connections share the slot of modules with the same name
+	var dbs []*Conn
+	for i := 0; i < 20; i++ {
+		db := open(t)
+		dbs = append(dbs, db)
+		checkNoError(t, db.CreateModule("shadow", shadowModule{}), "couldn't create module: %s")
+	}
+	for _, db := range dbs {
+		checkClose(db, t)
+	}
+
+	db := open(t)
+	for i := 0; i < 16; i++ {
+		checkNoError(t, db.CreateModule(fmt.Sprintf("shadow%d", i), shadowModule{}), "couldn't create module: %s")
+	}
+	err := db.CreateModule("shadow16", shadowModule{})
+	assert.T(t, err != nil, "expected error when there is no more slot")
+	checkClose(db, t)
+
+	// slots are released when the modules are destroyed
+	db = open(t)
+	defer checkClose(db, t)
+	checkNoError(t, db.CreateModule("shadow16", shadowModule{}), "couldn't create module: %s")
+	checkNoError(t, db.Exec("CREATE VIRTUAL TABLE vtab USING shadow16()"), "couldn't create virtual table: %s")
+	if VersionNumber() >= 3037000 {
+		var typ string
+		err = db.OneValue("SELECT type FROM pragma_table_list WHERE name = 'vtab_data'", &typ)
+		checkNoError(t, err, "couldn't list tables: %s")
+		assert.Equal(t, "shadow", typ)
+	}
+}